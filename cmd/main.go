@@ -1,20 +1,47 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"main.go/internal/assignment"
 	"main.go/internal/config"
+	apiKeyIssue "main.go/internal/http-server/handlers/apikey/issue"
+	apiKeyRevoke "main.go/internal/http-server/handlers/apikey/revoke"
+	"main.go/internal/http-server/handlers/health"
+	prGet "main.go/internal/http-server/handlers/pr/get"
+	"main.go/internal/http-server/handlers/pr/history"
+	prList "main.go/internal/http-server/handlers/pr/list"
 	"main.go/internal/http-server/handlers/pr/merge"
 	"main.go/internal/http-server/handlers/pr/reassign"
+	"main.go/internal/http-server/handlers/pr/review"
 	PrSave "main.go/internal/http-server/handlers/pr/save"
+	prStatus "main.go/internal/http-server/handlers/pr/status"
+	statsPullRequests "main.go/internal/http-server/handlers/stats/pullrequests"
+	statsReviewers "main.go/internal/http-server/handlers/stats/reviewers"
+	teamDeactivate "main.go/internal/http-server/handlers/team/deactivate"
 	teamGet "main.go/internal/http-server/handlers/team/get"
 	teamSave "main.go/internal/http-server/handlers/team/save"
 	setactive "main.go/internal/http-server/handlers/users/set_active"
 	getreview "main.go/internal/http-server/handlers/users/set_active/get"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/http-server/middleware/auth"
+	"main.go/internal/http-server/middleware/idempotency"
+	httpMetrics "main.go/internal/http-server/middleware/metrics"
+	"main.go/internal/metrics"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+	"main.go/internal/storage/memory"
 	"main.go/internal/storage/postgres"
 )
 
@@ -23,29 +50,93 @@ const (
 	envProd  = "prod"
 )
 
+const (
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+)
+
 func main() {
 	cfg := config.NewConfig()
 	log := setupLogger(cfg.Env)
+
+	// migrate status|up|down [N] - управление схемой БД без запуска сервиса
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	log.Info("starting rv-service", slog.String("env", cfg.Env))
 
-	storage, err := postgres.New(cfg.StoragePath)
+	storage, err := newStorage(cfg, log)
 	if err != nil {
-		log.Error("failed to init storage")
+		log.Error("failed to init storage", slog.String("driver", cfg.StorageDriver), slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
+	// Все обращения к хранилищу, включая подбор ревьюеров и идемпотентность, замеряются
+	registry := metrics.NewRegistry()
+	storage = metrics.NewStorage(storage, registry)
+	openPullRequests := metrics.NewOpenPullRequestsGauges(log, storage, registry)
+
+	strategies, err := assignment.NewRegistry(cfg.Assignment.Strategy, storage)
+	if err != nil {
+		log.Error("failed to init assignment strategies", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	picker := assignment.NewPicker(storage, strategies)
+
+	if !cfg.Auth.Enabled {
+		log.Warn("api key authentication is disabled, all requests run as admin")
+	}
+
+	drain := &health.Drain{}
+
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
+	router.Use(httpMetrics.New(registry))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Logger)
+	router.Use(actor.New())
+
+	// Проверки состояния доступны без API-ключа
+	router.Get("/healthz", health.NewLiveness())
+	router.Get("/readyz", health.NewReadiness(log, storage, drain, cfg.Health.Timeout))
+
+	// Роли, которым доступен маршрут; admin допускается всегда
+	var (
+		readers  = auth.Require(models.RoleTeamLead, models.RoleBot, models.RoleReadOnly)
+		writers  = auth.Require(models.RoleTeamLead, models.RoleBot)
+		managers = auth.Require(models.RoleTeamLead)
+		admins   = auth.Require()
+	)
+
+	// Идемпотентность - после проверки роли: сохранённый ответ не отдаётся ключу без доступа к маршруту
+	idem := idempotency.New(log, storage, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
 
-	router.Post("/team/add", teamSave.New(log, storage))
-	router.Get("/team/get", teamGet.New(log, storage))
-	router.Post("/users/setIsActive", setactive.New(log, storage))
-	router.Post("/pullRequest/create", PrSave.New(log, storage))
-	router.Post("/pullRequest/merge", merge.New(log, storage))
-	router.Post("/pullRequest/reassign", reassign.New(log, storage))
-	router.Get("/users/getReview", getreview.New(log, storage))
+	router.Group(func(r chi.Router) {
+		r.Use(auth.New(log, storage, cfg.Auth.Enabled, cfg.Auth.BootstrapKey))
+
+		r.With(managers, idem).Post("/team/add", teamSave.New(log, storage))
+		r.With(readers).Get("/team/get", teamGet.New(log, storage))
+		r.With(admins, idem).Post("/team/deactivateUsers", teamDeactivate.New(log, storage, picker))
+		r.With(admins, idem).Post("/users/setIsActive", setactive.New(log, storage, picker))
+		r.With(writers, idem).Post("/pullRequest/create", PrSave.New(log, storage, picker))
+		r.With(writers, idem).Post("/pullRequest/merge", merge.New(log, storage))
+		r.With(writers, idem).Post("/pullRequest/ready", prStatus.New(log, storage, picker, models.TransitionReady))
+		r.With(writers, idem).Post("/pullRequest/close", prStatus.New(log, storage, picker, models.TransitionClose))
+		r.With(writers, idem).Post("/pullRequest/reopen", prStatus.New(log, storage, picker, models.TransitionReopen))
+		r.With(writers, idem).Post("/pullRequest/reassign", reassign.New(log, storage, picker))
+		r.With(writers, idem).Post("/pullRequest/review", review.New(log, storage))
+		r.With(readers).Get("/pullRequest/get", prGet.New(log, storage))
+		r.With(readers).Get("/pullRequest/list", prList.New(log, storage))
+		r.With(readers).Get("/pullRequest/history", history.New(log, storage))
+		r.With(readers).Get("/users/getReview", getreview.New(log, storage))
+		r.With(readers).Get("/stats/reviewers", statsReviewers.New(log, storage))
+		r.With(readers).Get("/stats/pullRequests", statsPullRequests.New(log, storage))
+		r.With(admins, idem).Post("/apiKey/issue", apiKeyIssue.New(log, storage))
+		r.With(admins, idem).Post("/apiKey/revoke", apiKeyRevoke.New(log, storage))
+		// Метрики раскрывают названия команд и нагрузку, поэтому тоже требуют ключ
+		r.With(readers).Get("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
+	})
 
 	log.Info("starting server", slog.String("address", cfg.Address))
 
@@ -57,10 +148,87 @@ func main() {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Error("failed to start server")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Метрики открытых PR пересчитываются в фоне до сигнала остановки
+	metricsDone := make(chan struct{})
+	go func() {
+		defer close(metricsDone)
+		openPullRequests.Run(ctx, cfg.Metrics.RefreshInterval)
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
+		log.Error("failed to start server", slog.String("error", err.Error()))
+		storage.Close()
+		os.Exit(1)
+	case <-ctx.Done():
 	}
+	// Повторный сигнал завершает процесс сразу, не дожидаясь запросов
+	stop()
+	// Хранилище закрывается в shutdown, поэтому фоновый пересчёт метрик должен завершиться раньше
+	<-metricsDone
+
+	log.Info("shutting down server", slog.Duration("delay", cfg.ShutdownDelay), slog.Duration("timeout", cfg.ShutdownTimeout))
+	shutdown(log, srv, storage, drain, cfg.HTTPServer)
+	log.Info("server stopped")
+}
+
+// shutdown - плавная остановка: сначала /readyz начинает отвечать 503, затем сервер перестаёт
+// принимать соединения и дожидается выполняющихся запросов, и только после этого закрывается БД
+func shutdown(log *slog.Logger, srv *http.Server, storage storage.Storage, drain *health.Drain, cfg config.HTTPServer) {
+	// 1. Балансировщик перестаёт направлять трафик, пока сервер ещё принимает запросы
+	drain.Start()
+	time.Sleep(cfg.ShutdownDelay)
+
+	// 2. Закрываем слушатель и ждём выполняющиеся запросы не дольше ShutdownTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("failed to drain connections", slog.String("error", err.Error()))
+		srv.Close()
+	}
+
+	// 3. Запросов больше нет - закрываем соединения с БД
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", slog.String("error", err.Error()))
+	}
+}
+
+// newStorage - хранилище по storage_driver из конфига
+func newStorage(cfg *config.Config, log *slog.Logger) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case driverPostgres:
+		pg, err := postgres.New(cfg.StoragePath, cfg.QueryTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.AutoMigrate {
+			applied, err := pg.MigrateUp(context.Background())
+			for _, m := range applied {
+				log.Info("migration applied", slog.Int("version", m.Version), slog.String("name", m.Name))
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return pg, nil
+	case driverMemory:
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
 }
 
 func setupLogger(env string) *slog.Logger {
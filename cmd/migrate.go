@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"main.go/internal/config"
+	"main.go/internal/storage/postgres"
+)
+
+const migrateUsage = "usage: migrate status | up | down [N]"
+
+// runMigrate - подкоманда migrate: status - список миграций, up - применить все неприменённые,
+// down [N] - откатить N последних (по умолчанию одну). Возвращает код выхода
+func runMigrate(cfg *config.Config, args []string) int {
+	if cfg.StorageDriver != driverPostgres {
+		fmt.Fprintf(os.Stderr, "migrations are only supported for the %s storage driver\n", driverPostgres)
+		return 1
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	pg, err := postgres.New(cfg.StoragePath, cfg.QueryTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to init storage: %s\n", err)
+		return 1
+	}
+	defer pg.Close()
+
+	ctx := context.Background()
+	var applied []postgres.Migration
+	switch args[0] {
+	case "status":
+		migrations, err := pg.MigrationStatus(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get migration status: %s\n", err)
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, m := range migrations {
+			appliedAt := "pending"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", m.Version, m.Name, appliedAt)
+		}
+		w.Flush()
+		return 0
+
+	case "up":
+		applied, err = pg.MigrateUp(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+		applied, err = pg.MigrateDown(ctx, steps)
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	for _, m := range applied {
+		fmt.Printf("%s %04d_%s\n", args[0], m.Version, m.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate %s failed: %s\n", args[0], err)
+		return 1
+	}
+	if len(applied) == 0 {
+		fmt.Println("nothing to migrate")
+	}
+
+	return 0
+}
@@ -0,0 +1,43 @@
+package assignment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"main.go/internal/models"
+)
+
+// Ошибки проверки ревьювера, выбранного вручную
+var (
+	ErrCandidateIsAuthor  = errors.New("reviewer is the author of the pull request")
+	ErrCandidateAssigned  = errors.New("reviewer is already assigned to the pull request")
+	ErrCandidateInactive  = errors.New("reviewer is not active")
+	ErrCandidateNotInTeam = errors.New("reviewer is not in the author's team or its fallback teams")
+)
+
+// NotEnoughReviewersError - свободных кандидатов меньше, чем требует команда (team.MinReviewers)
+type NotEnoughReviewersError struct {
+	Required  int
+	Available int
+}
+
+func (e *NotEnoughReviewersError) Error() string {
+	return fmt.Sprintf("team requires at least %d reviewers, only %d available", e.Required, e.Available)
+}
+
+// CheckCandidate - проверить, что пользователя можно назначить ревьювером на PR автора из team:
+// он не автор, ещё не назначен, активен и состоит в команде автора или в одной из её резервных команд
+func CheckCandidate(team models.Team, pr models.PullRequest, candidate models.User) error {
+	switch {
+	case candidate.UserID == pr.AuthorID:
+		return ErrCandidateIsAuthor
+	case slices.Contains(pr.AssignedReviewers, candidate.UserID):
+		return ErrCandidateAssigned
+	case !candidate.IsActive:
+		return ErrCandidateInactive
+	case candidate.TeamName != team.TeamName && !slices.Contains(team.FallbackTeams, candidate.TeamName):
+		return ErrCandidateNotInTeam
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package assignment
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"main.go/internal/models"
+)
+
+// LeastLoaded - выбор ревьюеров с наименьшим числом назначений на открытые PR.
+// При равной нагрузке выбирает по user_id, чтобы результат был детерминированным
+type LeastLoaded struct {
+	loads LoadCounter
+}
+
+func NewLeastLoaded(loads LoadCounter) *LeastLoaded {
+	return &LeastLoaded{loads: loads}
+}
+
+// Pick - отсортировать кандидатов по (нагрузка, user_id) и взять первых n.
+// К нагрузке из БД добавляются назначения, уже запланированные в текущем плане переназначений
+func (s *LeastLoaded) Pick(ctx context.Context, _ models.PullRequest, candidates []string, n int) ([]string, error) {
+	const op = "assignment.LeastLoaded.Pick"
+
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	loads, err := s.openLoads(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sorted := slices.Clone(candidates)
+	slices.SortFunc(sorted, func(a, b string) int {
+		if c := cmp.Compare(loads[a]+plannedLoad(ctx, a), loads[b]+plannedLoad(ctx, b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	return sorted[:min(n, len(sorted))], nil
+}
+
+// openLoads - нагрузка кандидатов из БД. В рамках плана переназначений она читается
+// через транзакцию плана и запрашивается только для тех, чья нагрузка ещё не известна
+func (s *LeastLoaded) openLoads(ctx context.Context, candidates []string) (map[string]int, error) {
+	state := planStateFrom(ctx)
+	if state == nil {
+		return s.loads.GetOpenReviewLoad(ctx, candidates)
+	}
+
+	var unknown []string
+	for _, candidate := range candidates {
+		if _, ok := state.loads[candidate]; !ok {
+			unknown = append(unknown, candidate)
+		}
+	}
+
+	if len(unknown) > 0 {
+		loads, err := state.reader.GetOpenReviewLoad(ctx, unknown)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range unknown {
+			state.loads[candidate] = loads[candidate]
+		}
+	}
+
+	return state.loads, nil
+}
@@ -0,0 +1,120 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"main.go/internal/models"
+)
+
+// Store - источник кандидатов в ревьюеры и команд авторов
+type Store interface {
+	GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error)
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
+}
+
+// Selection - результат подбора ревьюеров
+type Selection struct {
+	Reviewers []string
+	// Fallback - ревьюеры из резервных команд: user_id -> имя резервной команды
+	Fallback map[string]string
+}
+
+// Picker - подбирает ревьюеров на PR по стратегии команды автора
+type Picker struct {
+	store      Store
+	strategies *Registry
+}
+
+func NewPicker(store Store, strategies *Registry) *Picker {
+	return &Picker{store: store, strategies: strategies}
+}
+
+// Pick - подобрать не более n ревьюеров из активных участников команды автора,
+// не включая автора и пользователей из exclude (например, уже назначенных).
+// Если в команде не хватает кандидатов до min(n, team.MinReviewers),
+// недостающие берутся из резервных команд в порядке приоритета
+func (p *Picker) Pick(ctx context.Context, team models.Team, pr models.PullRequest, exclude []string, n int) (*Selection, error) {
+	const op = "assignment.Picker.Pick"
+
+	strategy, err := p.strategies.ForTeam(team)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	selection := &Selection{}
+	exclude = append(slices.Clone(exclude), pr.AuthorID)
+
+	// 1. Сначала кандидаты из команды автора
+	picked, err := p.pickFromTeam(ctx, strategy, team.TeamName, pr, exclude, n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	selection.Reviewers = picked
+
+	// 2. Если команда исчерпана - добираем до обязательного минимума из резервных команд
+	required := min(n, team.MinReviewers)
+	for _, fallback := range team.FallbackTeams {
+		if len(selection.Reviewers) >= required {
+			break
+		}
+
+		exclude = append(exclude, picked...)
+		picked, err = p.pickFromTeam(ctx, strategy, fallback, pr, exclude, required-len(selection.Reviewers))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, reviewer := range picked {
+			if selection.Fallback == nil {
+				selection.Fallback = make(map[string]string)
+			}
+			selection.Fallback[reviewer] = fallback
+		}
+		selection.Reviewers = append(selection.Reviewers, picked...)
+	}
+
+	return selection, nil
+}
+
+// pickFromTeam - выбрать по стратегии до n активных участников команды, не входящих в exclude
+func (p *Picker) pickFromTeam(ctx context.Context, strategy Strategy, teamName string, pr models.PullRequest, exclude []string, n int) ([]string, error) {
+	members, err := p.activeMembers(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]string, 0, len(members))
+	for _, member := range members {
+		if !slices.Contains(exclude, member) {
+			candidates = append(candidates, member)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	return strategy.Pick(ctx, pr, candidates, n)
+}
+
+// activeMembers - активные участники команды (с кэшированием в рамках плана переназначений)
+func (p *Picker) activeMembers(ctx context.Context, teamName string) ([]string, error) {
+	state := planStateFrom(ctx)
+	if state == nil {
+		return p.store.GetActiveMembersByTeam(ctx, teamName)
+	}
+
+	if members, ok := state.members[teamName]; ok {
+		return members, nil
+	}
+
+	members, err := state.reader.GetActiveMembersByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	state.members[teamName] = members
+	return members, nil
+}
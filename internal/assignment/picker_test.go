@@ -0,0 +1,118 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+)
+
+// teams - активные участники по имени команды
+type teams map[string][]string
+
+func (s teams) GetActiveMembersByTeam(_ context.Context, teamName string) ([]string, error) {
+	return s[teamName], nil
+}
+
+func (s teams) GetTeamByUser(context.Context, string) (*models.Team, error) {
+	return nil, errors.New("not used")
+}
+
+func newPicker(t *testing.T, store teams) *Picker {
+	t.Helper()
+
+	strategies, err := NewRegistry(StrategyLeastLoaded, loads{})
+	if err != nil {
+		t.Fatalf("failed to init strategies: %v", err)
+	}
+	return NewPicker(store, strategies)
+}
+
+func TestPickerPick(t *testing.T) {
+	picker := newPicker(t, teams{
+		"backend":  {"u1", "u2", "u3", "u4"},
+		"platform": {"p1", "p2"},
+		"infra":    {"i1"},
+	})
+	pr := models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1"}
+
+	tests := []struct {
+		name      string
+		team      models.Team
+		exclude   []string
+		n         int
+		reviewers string
+		fallback  map[string]string
+	}{
+		{
+			name:      "author team is enough",
+			team:      models.Team{TeamName: "backend", MinReviewers: 2, FallbackTeams: []string{"platform"}},
+			n:         2,
+			reviewers: "u2,u3",
+		},
+		{
+			name:      "author and excluded are skipped",
+			team:      models.Team{TeamName: "backend", MinReviewers: 1},
+			exclude:   []string{"u2", "u3"},
+			n:         2,
+			reviewers: "u4",
+		},
+		{
+			name:      "fallback teams in order up to the minimum",
+			team:      models.Team{TeamName: "backend", MinReviewers: 2, FallbackTeams: []string{"infra", "platform"}},
+			exclude:   []string{"u2", "u3"},
+			n:         3,
+			reviewers: "u4,i1",
+			fallback:  map[string]string{"i1": "infra"},
+		},
+		{
+			name:      "several fallback teams",
+			team:      models.Team{TeamName: "backend", MinReviewers: 3, FallbackTeams: []string{"infra", "platform"}},
+			exclude:   []string{"u2", "u3", "u4"},
+			n:         3,
+			reviewers: "i1,p1,p2",
+			fallback:  map[string]string{"i1": "infra", "p1": "platform", "p2": "platform"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, err := picker.Pick(context.Background(), tt.team, pr, tt.exclude, tt.n)
+			if err != nil {
+				t.Fatalf("failed to pick: %v", err)
+			}
+			if got := strings.Join(selection.Reviewers, ","); got != tt.reviewers {
+				t.Errorf("reviewers = %s, want %s", got, tt.reviewers)
+			}
+			if !maps.Equal(selection.Fallback, tt.fallback) {
+				t.Errorf("fallback = %v, want %v", selection.Fallback, tt.fallback)
+			}
+		})
+	}
+}
+
+func TestCheckCandidate(t *testing.T) {
+	team := models.Team{TeamName: "backend", FallbackTeams: []string{"platform"}}
+	pr := models.PullRequest{AuthorID: "u1", AssignedReviewers: []string{"u2"}}
+
+	tests := []struct {
+		candidate models.User
+		want      error
+	}{
+		{models.User{UserID: "u1", TeamName: "backend", IsActive: true}, ErrCandidateIsAuthor},
+		{models.User{UserID: "u2", TeamName: "backend", IsActive: true}, ErrCandidateAssigned},
+		{models.User{UserID: "u3", TeamName: "backend", IsActive: false}, ErrCandidateInactive},
+		{models.User{UserID: "f1", TeamName: "frontend", IsActive: true}, ErrCandidateNotInTeam},
+		{models.User{UserID: "u3", TeamName: "backend", IsActive: true}, nil},
+		{models.User{UserID: "p1", TeamName: "platform", IsActive: true}, nil},
+	}
+
+	for _, tt := range tests {
+		if err := CheckCandidate(team, pr, tt.candidate); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.candidate.UserID, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+type planStateKey struct{}
+
+// planState - состояние построения плана. Кэширует составы команд и нагрузку из БД,
+// чтобы план на сотни PR не делал запросы на каждый PR, и учитывает назначения,
+// запланированные, но ещё не записанные в БД, чтобы least_loaded не отдавал все ревью одному человеку.
+// Данные читаются через reader - транзакцию хранилища, в которой план будет применён
+type planState struct {
+	reader  storage.PlanReader
+	members map[string][]string // активные участники по имени команды
+	loads   map[string]int      // нагрузка из БД по user_id
+	planned map[string]int      // запланированные назначения по user_id
+}
+
+func withPlanState(ctx context.Context, reader storage.PlanReader) context.Context {
+	return context.WithValue(ctx, planStateKey{}, &planState{
+		reader:  reader,
+		members: make(map[string][]string),
+		loads:   make(map[string]int),
+		planned: make(map[string]int),
+	})
+}
+
+func planStateFrom(ctx context.Context) *planState {
+	state, _ := ctx.Value(planStateKey{}).(*planState)
+	return state
+}
+
+func plannedLoad(ctx context.Context, userID string) int {
+	if state := planStateFrom(ctx); state != nil {
+		return state.planned[userID]
+	}
+	return 0
+}
+
+// PlanReassignments - подобрать замену уходящим ревьюерам (leaving) на открытых PR
+// по тем же правилам, что и при переназначении через /pullRequest/reassign.
+// Ревью, для которых замены не нашлось, в план не попадают. Подходит как storage.ReassignmentPlanner
+func (p *Picker) PlanReassignments(ctx context.Context, reader storage.PlanReader, prs []models.PullRequest, leaving []string) ([]models.Reassignment, error) {
+	const op = "assignment.Picker.PlanReassignments"
+
+	ctx = withPlanState(ctx, reader)
+	state := planStateFrom(ctx)
+	teams := make(map[string]*models.Team) // команда автора по author_id
+	var moves []models.Reassignment
+
+	for _, pr := range prs {
+		team, ok := teams[pr.AuthorID]
+		if !ok {
+			var err error
+			team, err = reader.GetTeamByUser(ctx, pr.AuthorID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			teams[pr.AuthorID] = team
+		}
+
+		exclude := append(slices.Clone(pr.AssignedReviewers), leaving...)
+		for _, reviewer := range pr.AssignedReviewers {
+			if !slices.Contains(leaving, reviewer) {
+				continue
+			}
+
+			move := models.Reassignment{PullRequestID: pr.PullRequestID, OldReviewerID: reviewer}
+
+			selection, err := p.Pick(ctx, *team, pr, exclude, 1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if len(selection.Reviewers) == 0 {
+				continue
+			}
+
+			move.NewReviewerID = selection.Reviewers[0]
+			move.FallbackTeam = selection.Fallback[move.NewReviewerID]
+			exclude = append(exclude, move.NewReviewerID)
+			state.planned[move.NewReviewerID]++
+			moves = append(moves, move)
+		}
+	}
+
+	return moves, nil
+}
@@ -0,0 +1,30 @@
+package assignment
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"main.go/internal/models"
+)
+
+// Random - случайный выбор ревьюеров
+type Random struct{}
+
+func NewRandom() *Random {
+	return &Random{}
+}
+
+// Pick - перемешать кандидатов и взять первых n
+func (s *Random) Pick(_ context.Context, _ models.PullRequest, candidates []string, n int) ([]string, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	shuffled := make([]string, len(candidates))
+	copy(shuffled, candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled[:min(n, len(shuffled))], nil
+}
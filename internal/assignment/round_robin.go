@@ -0,0 +1,47 @@
+package assignment
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"main.go/internal/models"
+)
+
+// RoundRobin - выбор ревьюеров по кругу в порядке user_id.
+// Запоминает последнего выбранного, поэтому корректно переживает изменение состава команды
+type RoundRobin struct {
+	mu   sync.Mutex
+	last string
+}
+
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{}
+}
+
+// Pick - взять n кандидатов, следующих за последним выбранным
+func (s *RoundRobin) Pick(_ context.Context, _ models.PullRequest, candidates []string, n int) ([]string, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	sorted := slices.Clone(candidates)
+	slices.Sort(sorted)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Первый кандидат строго после последнего выбранного, иначе начинаем сначала
+	start, _ := slices.BinarySearch(sorted, s.last)
+	if start < len(sorted) && sorted[start] == s.last {
+		start++
+	}
+
+	picked := make([]string, 0, min(n, len(sorted)))
+	for i := 0; i < len(sorted) && len(picked) < n; i++ {
+		picked = append(picked, sorted[(start+i)%len(sorted)])
+	}
+	s.last = picked[len(picked)-1]
+
+	return picked, nil
+}
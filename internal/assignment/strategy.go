@@ -0,0 +1,95 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"main.go/internal/models"
+)
+
+// Имена стратегий назначения ревьюеров (используются в конфиге и в настройках команды)
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+// ErrUnknownStrategy - стратегия с таким именем не поддерживается
+var ErrUnknownStrategy = errors.New("unknown assignment strategy")
+
+// Strategy - стратегия выбора ревьюеров из списка кандидатов
+type Strategy interface {
+	// Pick - выбрать не более n ревьюеров из candidates для pr
+	Pick(ctx context.Context, pr models.PullRequest, candidates []string, n int) ([]string, error)
+}
+
+// LoadCounter - источник данных о текущей нагрузке ревьюеров
+type LoadCounter interface {
+	GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+// IsKnown - проверить, поддерживается ли стратегия с таким именем
+func IsKnown(name string) bool {
+	switch name {
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded:
+		return true
+	}
+	return false
+}
+
+// Registry - выбирает стратегию для команды: из настроек команды или по умолчанию из конфига
+type Registry struct {
+	defaultName string
+	random      Strategy
+	leastLoaded Strategy
+
+	mu         sync.Mutex
+	roundRobin map[string]*RoundRobin // у каждой команды своя очередь
+}
+
+// NewRegistry - создать реестр стратегий, defaultName используется для команд без своей настройки
+func NewRegistry(defaultName string, loads LoadCounter) (*Registry, error) {
+	const op = "assignment.NewRegistry"
+
+	if !IsKnown(defaultName) {
+		return nil, fmt.Errorf("%s: %w: %q", op, ErrUnknownStrategy, defaultName)
+	}
+
+	return &Registry{
+		defaultName: defaultName,
+		random:      NewRandom(),
+		leastLoaded: NewLeastLoaded(loads),
+		roundRobin:  make(map[string]*RoundRobin),
+	}, nil
+}
+
+// ForTeam - получить стратегию для команды
+func (r *Registry) ForTeam(team models.Team) (Strategy, error) {
+	const op = "assignment.Registry.ForTeam"
+
+	name := team.AssignmentStrategy
+	if name == "" {
+		name = r.defaultName
+	}
+
+	switch name {
+	case StrategyRandom:
+		return r.random, nil
+	case StrategyLeastLoaded:
+		return r.leastLoaded, nil
+	case StrategyRoundRobin:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		rr, ok := r.roundRobin[team.TeamName]
+		if !ok {
+			rr = NewRoundRobin()
+			r.roundRobin[team.TeamName] = rr
+		}
+		return rr, nil
+	}
+
+	return nil, fmt.Errorf("%s: %w: %q", op, ErrUnknownStrategy, name)
+}
@@ -0,0 +1,110 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+)
+
+// loads - нагрузка ревьюеров для LeastLoaded
+type loads map[string]int
+
+func (l loads) GetOpenReviewLoad(_ context.Context, userIDs []string) (map[string]int, error) {
+	result := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		result[userID] = l[userID]
+	}
+	return result, nil
+}
+
+func pick(t *testing.T, strategy Strategy, candidates []string, n int) string {
+	t.Helper()
+
+	picked, err := strategy.Pick(context.Background(), models.PullRequest{}, candidates, n)
+	if err != nil {
+		t.Fatalf("failed to pick: %v", err)
+	}
+	return strings.Join(picked, ",")
+}
+
+func TestLeastLoadedPick(t *testing.T) {
+	strategy := NewLeastLoaded(loads{"u2": 2, "u3": 1})
+
+	// Сначала наименее загруженные, при равной нагрузке - по user_id
+	if got := pick(t, strategy, []string{"u2", "u3", "u5", "u4"}, 3); got != "u4,u5,u3" {
+		t.Errorf("picked = %s, want u4,u5,u3", got)
+	}
+	if got := pick(t, strategy, []string{"u2", "u3"}, 5); got != "u3,u2" {
+		t.Errorf("picked = %s, want all candidates u3,u2", got)
+	}
+	if got := pick(t, strategy, nil, 2); got != "" {
+		t.Errorf("picked = %s from no candidates", got)
+	}
+}
+
+func TestRoundRobinPick(t *testing.T) {
+	strategy := NewRoundRobin()
+	candidates := []string{"u4", "u2", "u3"}
+
+	var got []string
+	for range 4 {
+		got = append(got, pick(t, strategy, candidates, 1))
+	}
+	if strings.Join(got, " ") != "u2 u3 u4 u2" {
+		t.Errorf("picked = %v, want u2 u3 u4 u2", got)
+	}
+
+	// Последним выбран u2; после ухода u3 очередь продолжается со следующего за u2
+	if got := pick(t, strategy, []string{"u2", "u4", "u5"}, 2); got != "u4,u5" {
+		t.Errorf("picked = %s, want u4,u5", got)
+	}
+}
+
+func TestRandomPick(t *testing.T) {
+	strategy := NewRandom()
+	candidates := []string{"u2", "u3", "u4"}
+
+	for range 20 {
+		picked := strings.Split(pick(t, strategy, candidates, 2), ",")
+		if len(picked) != 2 || picked[0] == picked[1] {
+			t.Fatalf("picked = %v, want 2 distinct reviewers", picked)
+		}
+		for _, reviewer := range picked {
+			if !slices.Contains(candidates, reviewer) {
+				t.Fatalf("picked %s, not a candidate", reviewer)
+			}
+		}
+	}
+}
+
+func TestRegistryForTeam(t *testing.T) {
+	if _, err := NewRegistry("unknown", loads{}); !errors.Is(err, ErrUnknownStrategy) {
+		t.Fatalf("NewRegistry with unknown default: error = %v, want ErrUnknownStrategy", err)
+	}
+
+	registry, err := NewRegistry(StrategyLeastLoaded, loads{})
+	if err != nil {
+		t.Fatalf("failed to init strategies: %v", err)
+	}
+
+	strategy, err := registry.ForTeam(models.Team{TeamName: "backend"})
+	if _, ok := strategy.(*LeastLoaded); err != nil || !ok {
+		t.Errorf("default strategy = %T, %v, want *LeastLoaded", strategy, err)
+	}
+
+	// У каждой команды своя очередь round_robin
+	backend, _ := registry.ForTeam(models.Team{TeamName: "backend", AssignmentStrategy: StrategyRoundRobin})
+	again, _ := registry.ForTeam(models.Team{TeamName: "backend", AssignmentStrategy: StrategyRoundRobin})
+	frontend, _ := registry.ForTeam(models.Team{TeamName: "frontend", AssignmentStrategy: StrategyRoundRobin})
+	if backend != again || backend == frontend {
+		t.Errorf("round robin is not kept per team")
+	}
+
+	if _, err := registry.ForTeam(models.Team{TeamName: "backend", AssignmentStrategy: "unknown"}); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("ForTeam with unknown strategy: error = %v, want ErrUnknownStrategy", err)
+	}
+}
@@ -9,15 +9,69 @@ import (
 )
 
 type Config struct {
-	Env         string `yaml:"env" env-default:"local"`
-	StoragePath string `yaml:"storage_path" env-default:"postgres://postgres:postgres@db:5432/pr_db?sslmode=disable"`
-	HTTPServer  `yaml:"http_server"`
+	Env string `yaml:"env" env-default:"local"`
+	// StorageDriver - хранилище: postgres или memory (в памяти процесса, без БД)
+	StorageDriver string `yaml:"storage_driver" env-default:"postgres"`
+	StoragePath   string `yaml:"storage_path" env-default:"postgres://postgres:postgres@db:5432/pr_db?sslmode=disable"`
+	// AutoMigrate - применять неприменённые миграции при старте сервиса (иначе - командой migrate up).
+	// По умолчанию true; задаётся в NewConfig, так как env-default перезаписал бы явный false
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// QueryTimeout - предельное время одного обращения к хранилищу (0 - без ограничения)
+	QueryTimeout time.Duration `yaml:"query_timeout" env-default:"3s"`
+	HTTPServer   `yaml:"http_server"`
+	Assignment   `yaml:"assignment"`
+	Idempotency  `yaml:"idempotency"`
+	Health       `yaml:"health"`
+	Auth         `yaml:"auth"`
+	Metrics      `yaml:"metrics"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// ShutdownDelay - сколько /readyz отвечает 503 перед остановкой приёма соединений,
+	// чтобы балансировщик успел перестать направлять трафик
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env-default:"5s"`
+	// ShutdownTimeout - сколько ждать завершения выполняющихся запросов при остановке
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"20s"`
+}
+
+// Assignment - настройки назначения ревьюеров
+type Assignment struct {
+	// Strategy - стратегия по умолчанию для команд без своей настройки:
+	// random, round_robin или least_loaded
+	Strategy string `yaml:"strategy" env-default:"least_loaded"`
+}
+
+// Idempotency - настройки ключей идемпотентности POST-запросов
+type Idempotency struct {
+	// TTL - сколько хранится ответ на запрос с ключом
+	TTL time.Duration `yaml:"ttl" env-default:"24h"`
+	// Lease - сколько запрос держит ключ: незавершённый ключ старше Lease может занять повторный запрос.
+	// Должен быть больше http_server.timeout, обработчик прерывается по его истечении
+	Lease time.Duration `yaml:"lease" env-default:"10s"`
+}
+
+// Health - настройки проверок готовности
+type Health struct {
+	// Timeout - за сколько должны пройти все проверки /readyz
+	Timeout time.Duration `yaml:"timeout" env-default:"2s"`
+}
+
+// Auth - настройки аутентификации по API-ключам
+type Auth struct {
+	// Enabled - требовать API-ключ (false - все запросы выполняются с ролью admin, только для разработки).
+	// По умолчанию true; задаётся в NewConfig, так как env-default перезаписал бы явный false
+	Enabled bool `yaml:"enabled"`
+	// BootstrapKey - ключ с ролью admin из конфига, которым выдаются первые API-ключи
+	BootstrapKey string `yaml:"bootstrap_key" env:"AUTH_BOOTSTRAP_KEY"`
+}
+
+// Metrics - настройки доменных метрик
+type Metrics struct {
+	// RefreshInterval - как часто пересчитывать метрики открытых PR из хранилища
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"30s"`
 }
 
 func NewConfig() *Config {
@@ -25,7 +79,7 @@ func NewConfig() *Config {
 	if configPath == "" {
 		log.Printf("CONFIG_PATH is not set")
 	}
-	var cfg Config
+	cfg := Config{AutoMigrate: true, Auth: Auth{Enabled: true}}
 	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
 		log.Printf("cannot read config: %s", err)
 	}
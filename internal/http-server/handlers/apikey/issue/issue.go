@@ -0,0 +1,102 @@
+package issue
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/http-server/middleware/auth"
+	"main.go/internal/models"
+)
+
+// Request - структура запроса
+type Request struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// Response - структура ответа. Key возвращается только при выдаче, сервис хранит лишь его хэш
+type Response struct {
+	APIKey models.APIKey `json:"apikey"`
+	Key    string        `json:"key"`
+}
+
+// APIKeyCreatorInterface - интерфейс для выдачи ключа
+type APIKeyCreatorInterface interface {
+	CreateAPIKey(ctx context.Context, key models.APIKey, keyHash string) (*models.APIKey, error)
+}
+
+// New создаёт handler для POST /apiKey/issue
+func New(log *slog.Logger, keyCreator APIKeyCreatorInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.apikey.issue.New"
+
+		// 1. Декодируем JSON из тела запроса
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
+			response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "invalid request body",
+			})
+			return
+		}
+
+		// 2. Валидация - имя обязательно, роль из списка допустимых
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			log.Error("api key name is empty", slog.String("op", op))
+			response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "name can't be empty",
+			})
+			return
+		}
+
+		if !models.IsRole(req.Role) {
+			log.Error("unknown role", slog.String("op", op), slog.String("role", req.Role))
+			response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "role must be one of: " + strings.Join(models.Roles, ", "),
+			})
+			return
+		}
+
+		// 3. Генерируем ключ
+		keyID, key, err := auth.GenerateKey()
+		if err != nil {
+			log.Error("failed to generate api key", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 4. Сохраняем хэш ключа
+		created, err := keyCreator.CreateAPIKey(r.Context(), models.APIKey{
+			KeyID:     keyID,
+			Name:      req.Name,
+			Role:      req.Role,
+			CreatedBy: actor.FromContext(r.Context()),
+		}, auth.HashKey(key))
+		if err != nil {
+			log.Error("failed to save api key", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		log.Info("api key issued", slog.String("op", op),
+			slog.String("key_id", created.KeyID), slog.String("name", created.Name), slog.String("role", created.Role))
+
+		// 5. Возвращаем ключ. no-store: ответ с ключом не кэшируется и не сохраняется middleware идемпотентности
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Response{
+			APIKey: *created,
+			Key:    key,
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package revoke
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+)
+
+// Request - структура запроса
+type Request struct {
+	KeyID string `json:"keyid"`
+}
+
+// Response - структура ответа
+type Response struct {
+	APIKey models.APIKey `json:"apikey"`
+}
+
+// APIKeyRevokerInterface - интерфейс для отзыва ключа
+type APIKeyRevokerInterface interface {
+	RevokeAPIKey(ctx context.Context, keyID, actor string) (*models.APIKey, error)
+}
+
+// New создаёт handler для POST /apiKey/revoke. Повторный отзыв ключа не считается ошибкой
+func New(log *slog.Logger, keyRevoker APIKeyRevokerInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.apikey.revoke.New"
+
+		// 1. Декодируем JSON из тела запроса
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
+			response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "invalid request body",
+			})
+			return
+		}
+
+		// 2. Валидация - проверяем, что keyid не пустой
+		req.KeyID = strings.TrimSpace(req.KeyID)
+		if req.KeyID == "" {
+			log.Error("key id is empty", slog.String("op", op))
+			response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+				Code:    "INVALID_REQUEST",
+				Message: "keyid can't be empty",
+			})
+			return
+		}
+
+		// 3. Отзываем ключ
+		revoked, err := keyRevoker.RevokeAPIKey(r.Context(), req.KeyID, actor.FromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to revoke api key", slog.String("op", op), slog.String("key_id", req.KeyID), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		log.Info("api key revoked", slog.String("op", op), slog.String("key_id", revoked.KeyID))
+
+		// 4. Возвращаем отозванный ключ
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			APIKey: *revoked,
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"main.go/internal/storage"
+)
+
+// Статусы сервиса и компонентов
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Структура ответа
+type Response struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// Component - результат проверки компонента. Детали ошибки пишутся только в лог
+type Component struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Drain - признак остановки сервиса. После Start /readyz отвечает 503, не обращаясь к БД,
+// а уже принятые запросы продолжают обслуживаться
+type Drain struct {
+	draining atomic.Bool
+}
+
+// Start - начать остановку: сервис больше не готов принимать трафик
+func (d *Drain) Start() {
+	d.draining.Store(true)
+}
+
+// Draining - идёт ли остановка сервиса
+func (d *Drain) Draining() bool {
+	return d.draining.Load()
+}
+
+type ReadinessCheckerInterface interface {
+	Ping(ctx context.Context) error
+	CheckMigrations(ctx context.Context) error
+}
+
+// NewLiveness - процесс жив и обслуживает HTTP; зависимости не проверяются,
+// чтобы оркестратор не перезапускал сервис из-за недоступной БД
+func NewLiveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		write(w, http.StatusOK, Response{Status: StatusOK})
+	}
+}
+
+// NewReadiness - сервис готов принимать трафик: он не останавливается, БД отвечает на ping
+// и все миграции применены. Все проверки должны уложиться в timeout, иначе ответ 503
+func NewReadiness(log *slog.Logger, checker ReadinessCheckerInterface, drain *Drain, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.NewReadiness"
+
+		if drain.Draining() {
+			write(w, http.StatusServiceUnavailable, Response{
+				Status: StatusFail,
+				Components: map[string]Component{
+					"server": {Status: StatusFail, Error: "shutting down"},
+				},
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		checks := []struct {
+			name  string
+			check func(ctx context.Context) error
+		}{
+			{"database", checker.Ping},
+			{"migrations", checker.CheckMigrations},
+		}
+
+		resp := Response{Status: StatusOK, Components: make(map[string]Component, len(checks))}
+		for _, c := range checks {
+			err := c.check(ctx)
+			if err == nil {
+				resp.Components[c.name] = Component{Status: StatusOK}
+				continue
+			}
+
+			log.Error("readiness check failed", slog.String("op", op),
+				slog.String("component", c.name), slog.String("error", err.Error()))
+			resp.Status = StatusFail
+			resp.Components[c.name] = Component{Status: StatusFail, Error: reason(err)}
+		}
+
+		status := http.StatusOK
+		if resp.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		write(w, status, resp)
+	}
+}
+
+// reason - краткая причина отказа компонента для ответа
+func reason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, storage.ErrMigrationsPending):
+		return storage.ErrMigrationsPending.Error()
+	default:
+		return "unavailable"
+	}
+}
+
+func write(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
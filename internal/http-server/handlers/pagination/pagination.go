@@ -0,0 +1,75 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"main.go/internal/models"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Parse - разобрать query-параметры limit (по умолчанию DefaultLimit, не больше MaxLimit) и cursor
+func Parse(r *http.Request) (int, *models.PageCursor, error) {
+	query := r.URL.Query()
+
+	limit := DefaultLimit
+	if value := strings.TrimSpace(query.Get("limit")); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > MaxLimit {
+			return 0, nil, fmt.Errorf("limit must be an integer from 1 to %d", MaxLimit)
+		}
+		limit = n
+	}
+
+	after, err := DecodeCursor(strings.TrimSpace(query.Get("cursor")))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return limit, after, nil
+}
+
+// EncodeCursor - непрозрачная строка курсора для передачи клиенту (пустая для nil)
+func EncodeCursor(cursor *models.PageCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.PullRequestID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor - разобрать курсор, полученный от клиента (nil для пустой строки)
+func DecodeCursor(value string) (*models.PageCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	errInvalid := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	createdAt, pullRequestID, ok := strings.Cut(string(raw), "|")
+	if !ok || pullRequestID == "" {
+		return nil, errInvalid
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	return &models.PageCursor{CreatedAt: t, PullRequestID: pullRequestID}, nil
+}
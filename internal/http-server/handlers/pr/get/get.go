@@ -0,0 +1,62 @@
+package prGet
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/models"
+)
+
+// Response - структура ответа
+type Response struct {
+	PullRequest models.PullRequest `json:"pr"`
+}
+
+// PRGetterInterface - интерфейс для получения PR
+type PRGetterInterface interface {
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+}
+
+// New создаёт handler для GET /pullRequest/get
+func New(log *slog.Logger, prGetter PRGetterInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.pr.get.New"
+
+		// 1. Получаем query параметр pull_request_id
+		pullRequestID := strings.TrimSpace(r.URL.Query().Get("pull_request_id"))
+		if pullRequestID == "" {
+			log.Error("empty pull_request_id in query", slog.String("op", op))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "pull_request_id is required",
+				},
+			})
+			return
+		}
+
+		log.Info("getting pull request", slog.String("op", op), slog.String("pr_id", pullRequestID))
+
+		// 2. Получаем PR вместе с ревьюерами
+		pullRequest, err := prGetter.GetPullRequestByID(r.Context(), pullRequestID)
+		if err != nil {
+			log.Error("failed to get PR", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		if pullRequest.AssignedReviewers == nil {
+			pullRequest.AssignedReviewers = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{PullRequest: *pullRequest})
+	}
+}
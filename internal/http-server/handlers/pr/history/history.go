@@ -0,0 +1,74 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/models"
+)
+
+// Response - структура ответа
+type Response struct {
+	PullRequestID string                   `json:"pull_request_id"`
+	Events        []models.AssignmentEvent `json:"events"`
+}
+
+// PRHistoryInterface - интерфейс для получения журнала событий PR
+type PRHistoryInterface interface {
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+	GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]models.AssignmentEvent, error)
+}
+
+// New создаёт handler для GET /pullRequest/history
+func New(log *slog.Logger, historyGetter PRHistoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.pr.history.New"
+
+		// 1. Получаем query параметр pull_request_id
+		pullRequestID := strings.TrimSpace(r.URL.Query().Get("pull_request_id"))
+		if pullRequestID == "" {
+			log.Error("empty pull_request_id in query", slog.String("op", op))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "pull_request_id is required",
+				},
+			})
+			return
+		}
+
+		log.Info("getting pull request history", slog.String("op", op), slog.String("pr_id", pullRequestID))
+
+		// 2. Проверяем, что PR существует
+		if _, err := historyGetter.GetPullRequestByID(r.Context(), pullRequestID); err != nil {
+			log.Error("failed to get PR", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 3. Получаем журнал событий
+		events, err := historyGetter.GetPullRequestHistory(r.Context(), pullRequestID)
+		if err != nil {
+			log.Error("failed to get PR history", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		if events == nil {
+			events = []models.AssignmentEvent{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			PullRequestID: pullRequestID,
+			Events:        events,
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package prList
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"main.go/internal/http-server/handlers/pagination"
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/models"
+)
+
+// Response - структура ответа
+type Response struct {
+	PullRequests []models.PullRequest `json:"pullrequests"`
+	// NextCursor - курсор следующей страницы (пусто, если это последняя страница)
+	NextCursor string `json:"nextcursor,omitempty"`
+}
+
+// PRListerInterface - интерфейс для получения списка PR
+type PRListerInterface interface {
+	ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequest, *models.PageCursor, error)
+}
+
+// New создаёт handler для GET /pullRequest/list
+func New(log *slog.Logger, lister PRListerInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.pr.list.New"
+
+		// 1. Разбираем фильтры и параметры пагинации
+		filter, err := parseFilter(r)
+		if err != nil {
+			log.Error("invalid list filter", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+
+		log.Info("listing pull requests",
+			slog.String("op", op),
+			slog.String("status", filter.Status),
+			slog.String("author_id", filter.AuthorID),
+			slog.String("team_name", filter.TeamName),
+			slog.String("reviewer_id", filter.ReviewerID))
+
+		// 2. Получаем страницу PR
+		pullRequests, next, err := lister.ListPullRequests(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to list pull requests", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		if pullRequests == nil {
+			pullRequests = []models.PullRequest{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			PullRequests: pullRequests,
+			NextCursor:   pagination.EncodeCursor(next),
+		})
+	}
+}
+
+// parseFilter - разобрать query-параметры status, author_id, team_name, reviewer_id,
+// created_from/created_to, merged_from/merged_to (RFC 3339), limit и cursor
+func parseFilter(r *http.Request) (models.PullRequestFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.PullRequestFilter{
+		Status:     strings.TrimSpace(query.Get("status")),
+		AuthorID:   strings.TrimSpace(query.Get("author_id")),
+		TeamName:   strings.TrimSpace(query.Get("team_name")),
+		ReviewerID: strings.TrimSpace(query.Get("reviewer_id")),
+	}
+
+	if filter.Status != "" && !models.IsPullRequestStatus(filter.Status) {
+		return filter, fmt.Errorf("status must be one of %s", strings.Join(models.PullRequestStatuses, ", "))
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	} {
+		value := strings.TrimSpace(query.Get(param.name))
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be in RFC 3339 format", param.name)
+		}
+		*param.dst = &t
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, errors.New("created_from must be before created_to")
+	}
+	if filter.MergedFrom != nil && filter.MergedTo != nil && !filter.MergedFrom.Before(*filter.MergedTo) {
+		return filter, errors.New("merged_from must be before merged_to")
+	}
+
+	var err error
+	filter.Limit, filter.After, err = pagination.Parse(r)
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
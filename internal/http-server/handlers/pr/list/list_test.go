@@ -0,0 +1,138 @@
+package prList
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newHandler - обработчик поверх хранилища в памяти с n открытыми PR pr-1..pr-n автора u1
+func newHandler(t *testing.T, n int) (http.HandlerFunc, *memory.Storage) {
+	t.Helper()
+
+	store := memorytest.New(t, models.Team{Members: memorytest.Members(2)})
+	for i := 1; i <= n; i++ {
+		createPullRequest(t, store, fmt.Sprintf("pr-%d", i))
+	}
+
+	return New(memorytest.Logger(), store), store
+}
+
+func createPullRequest(t *testing.T, store *memory.Storage, id string) {
+	t.Helper()
+
+	err := store.CreatePullRequest(context.Background(), models.PullRequest{
+		PullRequestID:     id,
+		PullRequestName:   "Change " + id,
+		AuthorID:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("failed to create pull request %s: %v", id, err)
+	}
+}
+
+func list(t *testing.T, h http.HandlerFunc, query url.Values) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/pullRequest/list?"+query.Encode(), nil))
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func TestNewCursorPagination(t *testing.T) {
+	h, store := newHandler(t, 5)
+
+	seen := make(map[string]bool)
+	query := url.Values{"limit": {"2"}}
+	pages := 0
+	for {
+		rec, resp := list(t, h, query)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("page %d: status = %d, want %d: %s", pages+1, rec.Code, http.StatusOK, rec.Body)
+		}
+		pages++
+
+		if len(resp.PullRequests) > 2 {
+			t.Fatalf("page %d has %d pull requests, limit is 2", pages, len(resp.PullRequests))
+		}
+		for _, pr := range resp.PullRequests {
+			if seen[pr.PullRequestID] {
+				t.Errorf("page %d repeats %s", pages, pr.PullRequestID)
+			}
+			seen[pr.PullRequestID] = true
+		}
+
+		// PR, созданный между страницами, не сдвигает курсор
+		if pages == 1 {
+			createPullRequest(t, store, "pr-new")
+		}
+
+		if resp.NextCursor == "" {
+			break
+		}
+		if pages > 5 {
+			t.Fatalf("pagination does not terminate")
+		}
+		query.Set("cursor", resp.NextCursor)
+	}
+
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+	for i := 1; i <= 5; i++ {
+		if id := fmt.Sprintf("pr-%d", i); !seen[id] {
+			t.Errorf("%s was not listed", id)
+		}
+	}
+}
+
+func TestNewFilterByStatus(t *testing.T) {
+	h, store := newHandler(t, 3)
+
+	if _, err := store.MergePullRequest(context.Background(), "pr-2", nil, "test"); err != nil {
+		t.Fatalf("failed to merge pull request: %v", err)
+	}
+
+	rec, resp := list(t, h, url.Values{"status": {models.StatusMerged}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(resp.PullRequests) != 1 || resp.PullRequests[0].PullRequestID != "pr-2" {
+		t.Errorf("pull requests = %+v, want only pr-2", resp.PullRequests)
+	}
+	if resp.NextCursor != "" {
+		t.Errorf("nextcursor = %q, want empty on the last page", resp.NextCursor)
+	}
+}
+
+func TestNewInvalidQuery(t *testing.T) {
+	h, _ := newHandler(t, 1)
+
+	for _, query := range []url.Values{
+		{"cursor": {"not-a-cursor"}},
+		{"limit": {"0"}},
+		{"status": {"UNKNOWN"}},
+		{"created_from": {"yesterday"}},
+	} {
+		if rec, _ := list(t, h, query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d: %s", query.Encode(), rec.Code, http.StatusBadRequest, rec.Body)
+		}
+	}
+}
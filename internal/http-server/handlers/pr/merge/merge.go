@@ -1,16 +1,24 @@
 package merge
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/http-server/middleware/auth"
 	"main.go/internal/models"
 )
 
 // Request - структура запроса
 type Request struct {
 	PullRequestID string `json:"pull_request_id"`
+	// Override - слить в обход политики команды (для экстренных случаев), OverrideReason обязателен
+	Override       bool   `json:"override"`
+	OverrideReason string `json:"override_reason"`
 }
 
 // Response - структура ответа
@@ -20,7 +28,7 @@ type Response struct {
 
 // PRMergerInterface - интерфейс для merge операции
 type PRMergerInterface interface {
-	MergePullRequest(pullRequestID string) (*models.PullRequest, error)
+	MergePullRequest(ctx context.Context, pullRequestID string, override *models.MergeOverride, actor string) (*models.PullRequest, error)
 }
 
 // New создаёт handler для POST /pullRequest/merge
@@ -58,20 +66,40 @@ func New(log *slog.Logger, prMerger PRMergerInterface) http.HandlerFunc {
 			return
 		}
 
-		log.Info("merging pull request", slog.String("op", op), slog.String("pr_id", req.PullRequestID))
+		var override *models.MergeOverride
+		if req.Override {
+			if strings.TrimSpace(req.OverrideReason) == "" {
+				log.Error("override without reason", slog.String("op", op))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(models.ErrorResponse{
+					Error: models.ErrorDetail{
+						Code:    "INVALID_REQUEST",
+						Message: "override_reason is required for override",
+					},
+				})
+				return
+			}
+
+			// Слить в обход политики команды может только администратор
+			if role := auth.RoleFromContext(r.Context()); role != models.RoleAdmin {
+				log.Error("override is not allowed", slog.String("op", op), slog.String("role", role))
+				response.Write(w, http.StatusForbidden, models.ErrorDetail{
+					Code:    "FORBIDDEN",
+					Message: "only admin can merge with override",
+				})
+				return
+			}
+			override = &models.MergeOverride{Reason: strings.TrimSpace(req.OverrideReason)}
+		}
+
+		log.Info("merging pull request", slog.String("op", op), slog.String("pr_id", req.PullRequestID), slog.Bool("override", req.Override))
 
 		// 3. Мержим PR
-		pullRequest, err := prMerger.MergePullRequest(req.PullRequestID)
+		pullRequest, err := prMerger.MergePullRequest(r.Context(), req.PullRequestID, override, actor.FromContext(r.Context()))
 		if err != nil {
 			log.Error("failed to merge PR", slog.String("op", op), slog.String("pr_id", req.PullRequestID), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NOT_FOUND",
-					Message: "pull request not found",
-				},
-			})
+			response.Error(w, err)
 			return
 		}
 
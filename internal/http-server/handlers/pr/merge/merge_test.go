@@ -0,0 +1,147 @@
+package merge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/internal/http-server/middleware/auth"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newStore - хранилище в памяти с командой, требующей одно одобрение, и открытым PR pr-1
+// автора u1 с ревьюерами u2 и u3
+func newStore(t *testing.T) *memory.Storage {
+	t.Helper()
+
+	requiredApprovals, noChangesRequested := 1, true
+	return memorytest.New(t, models.Team{
+		RequiredApprovals:  &requiredApprovals,
+		NoChangesRequested: &noChangesRequested,
+		Members:            memorytest.Members(3),
+	}, models.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		AssignedReviewers: []string{"u2", "u3"},
+	})
+}
+
+func merge(t *testing.T, ctx context.Context, store *memory.Storage, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	h := New(memorytest.Logger(), store)
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequestWithContext(ctx, http.MethodPost, "/pullRequest/merge", strings.NewReader(body)))
+	return rec
+}
+
+func TestNewPolicyViolation(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	if err := store.SubmitReview(ctx, "pr-1", "u3", models.DecisionRequestChanges, "", "u3"); err != nil {
+		t.Fatalf("failed to submit review: %v", err)
+	}
+
+	rec := merge(t, ctx, store, `{"pull_request_id":"pr-1"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+
+	var resp struct {
+		Error struct {
+			Code    string                  `json:"code"`
+			Details []models.UnmetCondition `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != "MERGE_POLICY_VIOLATION" {
+		t.Errorf("code = %q, want MERGE_POLICY_VIOLATION", resp.Error.Code)
+	}
+
+	var conditions []string
+	for _, unmet := range resp.Error.Details {
+		conditions = append(conditions, unmet.Condition)
+	}
+	want := models.ConditionMinApprovals + "," + models.ConditionNoChangesRequested
+	if got := strings.Join(conditions, ","); got != want {
+		t.Errorf("unmet conditions = %s, want %s", got, want)
+	}
+
+	pr, err := store.GetPullRequestByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+	if pr.Status != models.StatusOpen {
+		t.Errorf("status after rejected merge = %q, want %q", pr.Status, models.StatusOpen)
+	}
+}
+
+func TestNewPolicySatisfied(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	if err := store.SubmitReview(ctx, "pr-1", "u2", models.DecisionApprove, "", "u2"); err != nil {
+		t.Fatalf("failed to submit review: %v", err)
+	}
+
+	rec := merge(t, ctx, store, `{"pull_request_id":"pr-1"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.PullRequest.Status != models.StatusMerged {
+		t.Errorf("status = %q, want %q", resp.PullRequest.Status, models.StatusMerged)
+	}
+
+	// Повторное слияние идемпотентно
+	if rec := merge(t, ctx, store, `{"pull_request_id":"pr-1"}`); rec.Code != http.StatusOK {
+		t.Fatalf("repeated merge: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+}
+
+func TestNewOverride(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		body   string
+		status int
+	}{
+		{"without reason", models.RoleAdmin, `{"pull_request_id":"pr-1","override":true}`, http.StatusBadRequest},
+		{"not admin", models.RoleBot, `{"pull_request_id":"pr-1","override":true,"override_reason":"hotfix"}`, http.StatusForbidden},
+		{"admin", models.RoleAdmin, `{"pull_request_id":"pr-1","override":true,"override_reason":"hotfix"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore(t)
+			ctx := auth.WithKey(context.Background(), &models.APIKey{KeyID: "k1", Name: "ops", Role: tt.role})
+
+			rec := merge(t, ctx, store, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestNewNotFound(t *testing.T) {
+	store := newStore(t)
+
+	rec := merge(t, context.Background(), store, `{"pull_request_id":"pr-404"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+}
@@ -1,35 +1,49 @@
 package reassign
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 
+	"main.go/internal/assignment"
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
 	"main.go/internal/models"
+	"main.go/internal/storage"
 )
 
 // Request - структура запроса
 type Request struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_reviewer_id"`
+	NewUserID     string `json:"new_reviewer_id"` // необязательно: назначить конкретного ревьювера
 }
 
 // Response - структура ответа
 type Response struct {
 	PullRequest models.PullRequest `json:"pr"`
 	ReplacedBy  string             `json:"replaced_by"`
+	// FallbackTeam - резервная команда, из которой взят новый ревьювер (пусто, если из команды автора)
+	FallbackTeam string `json:"fallbackteam,omitempty"`
 }
 
 // PRReassignInterface - интерфейс для операции переназначения ревьювера
 type PRReassignInterface interface {
-	GetPullRequestByID(pullRequestID string) (*models.PullRequest, error)
-	IsReviewerAssigned(pullRequestID, userID string) (bool, error)
-	GetActiveTeamMembers(authorID string) ([]string, error)
-	ReassignReviewer(pullRequestID, oldReviewerID, newReviewerID string) error
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+	IsReviewerAssigned(ctx context.Context, pullRequestID, userID string) (bool, error)
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID, newReviewerID, actor string) error
+}
+
+// ReviewerPicker - подбор ревьюеров по стратегии команды
+type ReviewerPicker interface {
+	Pick(ctx context.Context, team models.Team, pr models.PullRequest, exclude []string, n int) (*assignment.Selection, error)
 }
 
 // New создаёт handler для POST /pullRequest/reassign
-func New(log *slog.Logger, reassigner PRReassignInterface) http.HandlerFunc {
+func New(log *slog.Logger, reassigner PRReassignInterface, picker ReviewerPicker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.pr.reassign.New"
 
@@ -69,113 +83,96 @@ func New(log *slog.Logger, reassigner PRReassignInterface) http.HandlerFunc {
 			slog.String("old_reviewer", req.OldUserID))
 
 		// 3. Получаем PR по ID
-		pullRequest, err := reassigner.GetPullRequestByID(req.PullRequestID)
+		pullRequest, err := reassigner.GetPullRequestByID(r.Context(), req.PullRequestID)
 		if err != nil {
 			log.Error("failed to get PR", slog.String("op", op), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NOT_FOUND",
-					Message: "pull request not found",
-				},
-			})
+			response.Error(w, err)
 			return
 		}
 
-		// 4. Проверяем, что PR в статусе OPEN (не MERGED)
-		if pullRequest.Status == "MERGED" {
+		// 4. Проверяем, что PR в статусе OPEN: на слитых, закрытых и черновиках ревью не идёт
+		if pullRequest.Status == models.StatusMerged {
 			log.Error("cannot reassign on merged PR", slog.String("op", op), slog.String("pr_id", req.PullRequestID))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusConflict)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "PR_MERGED",
-					Message: "cannot reassign on merged PR",
-				},
-			})
+			response.Error(w, storage.ErrPRMerged)
+			return
+		}
+
+		if pullRequest.Status != models.StatusOpen {
+			log.Error("cannot reassign on not open PR", slog.String("op", op),
+				slog.String("pr_id", req.PullRequestID), slog.String("status", pullRequest.Status))
+			response.Error(w, storage.ErrPRNotOpen)
 			return
 		}
 
 		// 5. Проверяем, что старый ревьювер действительно назначен на этот PR
-		isAssigned, err := reassigner.IsReviewerAssigned(req.PullRequestID, req.OldUserID)
+		isAssigned, err := reassigner.IsReviewerAssigned(r.Context(), req.PullRequestID, req.OldUserID)
 		if err != nil {
 			log.Error("failed to check reviewer assignment", slog.String("op", op), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to check reviewer assignment",
-				},
-			})
+			response.Error(w, err)
 			return
 		}
 
 		if !isAssigned {
 			log.Error("reviewer not assigned", slog.String("op", op), slog.String("pr_id", req.PullRequestID), slog.String("old_reviewer", req.OldUserID))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusConflict)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NOT_ASSIGNED",
-					Message: "reviewer is not assigned to this PR",
-				},
-			})
+			response.Error(w, storage.ErrNotAssigned)
 			return
 		}
 
-		// 6. Получаем всех активных членов команды автора
-		teamMembers, err := reassigner.GetActiveTeamMembers(pullRequest.AuthorID)
+		// 6. Получаем команду автора (в ней хранится стратегия назначения)
+		team, err := reassigner.GetTeamByUser(r.Context(), pullRequest.AuthorID)
 		if err != nil {
-			log.Error("failed to get team members", slog.String("op", op), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to get team members",
-				},
-			})
+			log.Error("failed to get author team", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
 			return
 		}
 
-		// 7. Находим нового ревьювера (первого из доступных, кто не старый ревьювер)
-		var newReviewerID string
-		for _, member := range teamMembers {
-			// Не берем старого ревьювера и не берем текущих ревьюверов
-			if member != req.OldUserID && !contains(pullRequest.AssignedReviewers, member) {
-				newReviewerID = member
-				break
+		// 7. Определяем нового ревьювера: указанного вручную или подобранного по стратегии команды
+		var newReviewerID, fallbackTeam string
+		if req.NewUserID != "" {
+			candidate, err := reassigner.GetUser(r.Context(), req.NewUserID)
+			if err != nil {
+				log.Error("new reviewer not found", slog.String("op", op), slog.String("new_reviewer", req.NewUserID), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
 			}
-		}
 
-		// 8. Если нет доступного кандидата
-		if newReviewerID == "" {
-			log.Error("no available replacement candidate", slog.String("op", op), slog.String("pr_id", req.PullRequestID))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusConflict)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NO_CANDIDATE",
-					Message: "no active replacement candidate in team",
-				},
-			})
-			return
+			if err := assignment.CheckCandidate(*team, *pullRequest, *candidate); err != nil {
+				log.Error("new reviewer can't be assigned", slog.String("op", op),
+					slog.String("new_reviewer", req.NewUserID), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			newReviewerID = candidate.UserID
+			if candidate.TeamName != team.TeamName {
+				fallbackTeam = candidate.TeamName
+			}
+		} else {
+			// Исключаем текущих ревьюеров; если в команде автора никого не осталось - берём из резервных команд
+			selection, err := picker.Pick(r.Context(), *team, *pullRequest, pullRequest.AssignedReviewers, 1)
+			if err != nil {
+				log.Error("failed to pick reviewer", slog.String("op", op), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			// 8. Если нет доступного кандидата
+			if len(selection.Reviewers) == 0 {
+				log.Error("no available replacement candidate", slog.String("op", op), slog.String("pr_id", req.PullRequestID))
+				response.Error(w, storage.ErrNoCandidate)
+				return
+			}
+
+			newReviewerID = selection.Reviewers[0]
+			fallbackTeam = selection.Fallback[newReviewerID]
 		}
 
-		// 9. Выполняем переназначение ревьювера
-		err = reassigner.ReassignReviewer(req.PullRequestID, req.OldUserID, newReviewerID)
+		// 9. Выполняем переназначение ревьювера. Хранилище повторяет проверки статуса PR и кандидата
+		// под блокировкой: если PR или кандидат изменились после шагов 3-7, вернётся 409
+		err = reassigner.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, newReviewerID, actor.FromContext(r.Context()))
 		if err != nil {
 			log.Error("failed to reassign reviewer", slog.String("op", op), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to reassign reviewer",
-				},
-			})
+			response.Error(w, err)
 			return
 		}
 
@@ -199,18 +196,9 @@ func New(log *slog.Logger, reassigner PRReassignInterface) http.HandlerFunc {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(Response{
-			PullRequest: *pullRequest,
-			ReplacedBy:  newReviewerID,
+			PullRequest:  *pullRequest,
+			ReplacedBy:   newReviewerID,
+			FallbackTeam: fallbackTeam,
 		})
 	}
 }
-
-// contains - вспомогательная функция для проверки наличия элемента в slice
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,144 @@
+package reassign
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newHandler - обработчик поверх хранилища в памяти с командой из u1..u4
+// и открытым PR pr-1 автора u1 с ревьюерами u2 и u3
+func newHandler(t *testing.T) (http.HandlerFunc, *memory.Storage) {
+	t.Helper()
+
+	store := memorytest.New(t, models.Team{Members: memorytest.Members(4)}, models.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		AssignedReviewers: []string{"u2", "u3"},
+	})
+
+	return New(memorytest.Logger(), store, memorytest.Picker(t, store)), store
+}
+
+func reassign(t *testing.T, h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", strings.NewReader(body)))
+	return rec
+}
+
+func TestNewPicksReplacement(t *testing.T) {
+	h, store := newHandler(t)
+
+	rec := reassign(t, h, `{"pull_request_id":"pr-1","old_reviewer_id":"u2"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// Автор и текущие ревьюеры исключены - остаётся только u4
+	if resp.ReplacedBy != "u4" {
+		t.Errorf("replaced_by = %q, want u4", resp.ReplacedBy)
+	}
+
+	pr, err := store.GetPullRequestByID(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+	reviewers := slices.Sorted(slices.Values(pr.AssignedReviewers))
+	if got := strings.Join(reviewers, ","); got != "u3,u4" {
+		t.Errorf("stored reviewers = %s, want u3,u4", got)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, store *memory.Storage)
+		body    string
+		status  int
+		code    string
+	}{
+		{
+			name:   "not assigned",
+			body:   `{"pull_request_id":"pr-1","old_reviewer_id":"u4"}`,
+			status: http.StatusConflict,
+			code:   "NOT_ASSIGNED",
+		},
+		{
+			name:   "already assigned",
+			body:   `{"pull_request_id":"pr-1","old_reviewer_id":"u2","new_reviewer_id":"u3"}`,
+			status: http.StatusConflict,
+			code:   "ALREADY_ASSIGNED",
+		},
+		{
+			name:   "author",
+			body:   `{"pull_request_id":"pr-1","old_reviewer_id":"u2","new_reviewer_id":"u1"}`,
+			status: http.StatusConflict,
+			code:   "REVIEWER_IS_AUTHOR",
+		},
+		{
+			name: "no candidate",
+			prepare: func(t *testing.T, store *memory.Storage) {
+				if _, err := store.SetUserActive(context.Background(), "u4", false, "test"); err != nil {
+					t.Fatalf("failed to deactivate user: %v", err)
+				}
+			},
+			body:   `{"pull_request_id":"pr-1","old_reviewer_id":"u2"}`,
+			status: http.StatusConflict,
+			code:   "NO_CANDIDATE",
+		},
+		{
+			name: "merged",
+			prepare: func(t *testing.T, store *memory.Storage) {
+				if _, err := store.MergePullRequest(context.Background(), "pr-1", nil, "test"); err != nil {
+					t.Fatalf("failed to merge pull request: %v", err)
+				}
+			},
+			body:   `{"pull_request_id":"pr-1","old_reviewer_id":"u2"}`,
+			status: http.StatusConflict,
+			code:   "PR_MERGED",
+		},
+		{
+			name:   "pull request not found",
+			body:   `{"pull_request_id":"pr-404","old_reviewer_id":"u2"}`,
+			status: http.StatusNotFound,
+			code:   "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newHandler(t)
+			if tt.prepare != nil {
+				tt.prepare(t, store)
+			}
+
+			rec := reassign(t, h, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			var resp models.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q", resp.Error.Code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Request - структура запроса
+type Request struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Decision      string `json:"decision"` // APPROVE, REQUEST_CHANGES или COMMENT
+	Comment       string `json:"comment"`  // обязателен для COMMENT
+}
+
+// Response - структура ответа
+type Response struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Reviews       []models.Review `json:"reviews"`
+}
+
+// PRReviewInterface - интерфейс для записи решений ревьюеров
+type PRReviewInterface interface {
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+	SubmitReview(ctx context.Context, pullRequestID, reviewerID, decision, comment, actor string) error
+	GetPullRequestReviews(ctx context.Context, pullRequestID string) ([]models.Review, error)
+}
+
+// New создаёт handler для POST /pullRequest/review
+func New(log *slog.Logger, reviewer PRReviewInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.pr.review.New"
+
+		// 1. Декодируем JSON из тела запроса
+		var req Request
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "invalid request body",
+				},
+			})
+			return
+		}
+
+		// 2. Валидация - поля заполнены, решение известно, у комментария есть текст
+		if req.PullRequestID == "" || req.ReviewerID == "" {
+			log.Error("empty fields in request", slog.String("op", op))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "pull_request_id and reviewer_id are required",
+				},
+			})
+			return
+		}
+
+		if _, ok := models.ReviewStateOf(req.Decision); !ok || req.Decision == models.DecisionComment && req.Comment == "" {
+			log.Error("invalid decision", slog.String("op", op), slog.String("decision", req.Decision))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "decision must be APPROVE, REQUEST_CHANGES or COMMENT (with comment)",
+				},
+			})
+			return
+		}
+
+		log.Info("submitting review",
+			slog.String("op", op),
+			slog.String("pr_id", req.PullRequestID),
+			slog.String("reviewer", req.ReviewerID),
+			slog.String("decision", req.Decision))
+
+		// 3. Получаем PR по ID
+		pullRequest, err := reviewer.GetPullRequestByID(r.Context(), req.PullRequestID)
+		if err != nil {
+			log.Error("failed to get PR", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 4. Ревью принимаются только на открытых PR и только от назначенных ревьюеров
+		if pullRequest.Status != models.StatusOpen {
+			log.Error("cannot review not open PR", slog.String("op", op),
+				slog.String("pr_id", req.PullRequestID), slog.String("status", pullRequest.Status))
+			response.Error(w, storage.ErrPRNotOpen)
+			return
+		}
+
+		if !slices.Contains(pullRequest.AssignedReviewers, req.ReviewerID) {
+			log.Error("reviewer not assigned", slog.String("op", op),
+				slog.String("pr_id", req.PullRequestID), slog.String("reviewer", req.ReviewerID))
+			response.Error(w, storage.ErrNotAssigned)
+			return
+		}
+
+		// 5. Записываем решение
+		err = reviewer.SubmitReview(r.Context(), req.PullRequestID, req.ReviewerID, req.Decision, req.Comment, actor.FromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to submit review", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 6. Возвращаем состояния ревью всех ревьюеров PR
+		reviews, err := reviewer.GetPullRequestReviews(r.Context(), req.PullRequestID)
+		if err != nil {
+			log.Error("failed to get reviews", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		log.Info("review submitted successfully",
+			slog.String("op", op),
+			slog.String("pr_id", req.PullRequestID),
+			slog.String("reviewer", req.ReviewerID))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			PullRequestID: req.PullRequestID,
+			Reviews:       reviews,
+		})
+	}
+}
@@ -1,35 +1,48 @@
 package PrSave
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
+	"main.go/internal/assignment"
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
 	"main.go/internal/models"
+	"main.go/internal/storage"
 )
 
 type Request struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	// Draft - создать черновик: ревьюеры назначаются при переводе в OPEN через /pullRequest/ready
+	Draft bool `json:"draft"`
 }
 
 type Response struct {
 	PullRequest models.PullRequest `json:"pr"`
+	// FallbackReviewers - ревьюеры из резервных команд: user_id -> имя команды
+	FallbackReviewers map[string]string `json:"fallbackreviewers,omitempty"`
 }
 
 type PRSeverInterface interface {
-	CreatePullRequest(models.PullRequest) error
-	GetActiveTeamMembers(string) ([]string, error)
-	CheckAuthorExist(string) error
+	CreatePullRequest(ctx context.Context, pr models.PullRequest, actor string) error
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
 }
 
-func New(log *slog.Logger, prSaver PRSeverInterface) http.HandlerFunc {
+// ReviewerPicker - подбор ревьюеров по стратегии команды
+type ReviewerPicker interface {
+	Pick(ctx context.Context, team models.Team, pr models.PullRequest, exclude []string, n int) (*assignment.Selection, error)
+}
+
+func New(log *slog.Logger, prSaver PRSeverInterface, picker ReviewerPicker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.pr.save.New"
 		// 1.Декодируем json
 		var req Request
-		var assignedMembers []string
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
 			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
@@ -64,61 +77,77 @@ func New(log *slog.Logger, prSaver PRSeverInterface) http.HandlerFunc {
 			PullRequestName: req.PullRequestName,
 			AuthorID:        req.AuthorID,
 		}
-		// 3. Получаем активных членов команды
-		assignedMembers, err = prSaver.GetActiveTeamMembers(req.AuthorID)
-		if err != nil {
-			log.Error("failed to get team members", slog.String("op", op), slog.String("error", err.Error()))
+		// 3. Черновик создаём без ревьюеров; если автора нет, создание вернёт storage.ErrAuthorNotFound
+		if req.Draft {
+			pullRequest.Status = models.StatusDraft
+			pullRequest.AssignedReviewers = []string{}
+
+			err := prSaver.CreatePullRequest(r.Context(), pullRequest, actor.FromContext(r.Context()))
+			if err != nil {
+				log.Error("failed to create PR", slog.String("op", op), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			log.Info("draft PR created successfully", slog.String("op", op), slog.String("pr_id", req.PullRequestID))
+
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to get team members",
-				},
-			})
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(Response{PullRequest: pullRequest})
+			return
+		}
+
+		// 4. Получаем команду автора (в ней хранится стратегия назначения)
+		team, err := prSaver.GetTeamByUser(r.Context(), req.AuthorID)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("author not found", slog.String("op", op), slog.String("author_id", req.AuthorID))
+			response.Error(w, storage.ErrAuthorNotFound)
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get author team", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
 			return
 		}
 
+		// 5. Подбираем до team.MaxReviewers ревьюеров по стратегии команды
+		// (при нехватке кандидатов в команде - из резервных команд)
+		selection, err := picker.Pick(r.Context(), *team, pullRequest, nil, team.MaxReviewers)
+		if err != nil {
+			log.Error("failed to pick reviewers", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		assignedMembers := selection.Reviewers
 		if len(assignedMembers) == 0 {
 			log.Error("no active team members found", slog.String("op", op))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NO_CANDIDATE",
-					Message: "no active team members to assign as reviewers",
-				},
-			})
+			response.Error(w, storage.ErrNoCandidate)
 			return
 		}
 
-		// 4. Берём максимум 2 ревьювера (обрезаем slice)
-		maxReviewers := 2
-		if len(assignedMembers) < 2 {
-			maxReviewers = len(assignedMembers)
+		// Команда может требовать больше ревьюеров, чем есть свободных кандидатов
+		if len(assignedMembers) < team.MinReviewers {
+			log.Error("not enough reviewers", slog.String("op", op),
+				slog.Int("min_reviewers", team.MinReviewers), slog.Int("available", len(assignedMembers)))
+			response.Error(w, &assignment.NotEnoughReviewersError{Required: team.MinReviewers, Available: len(assignedMembers)})
+			return
 		}
-		assignedMembers = assignedMembers[:maxReviewers] // ✅ просто обрезаем
 
-		// 5. Создаём PR
+		// 6. Создаём PR
 		pullRequest = models.PullRequest{
 			PullRequestID:     req.PullRequestID,
 			PullRequestName:   req.PullRequestName,
 			AuthorID:          req.AuthorID,
-			Status:            "OPEN",
+			Status:            models.StatusOpen,
 			AssignedReviewers: assignedMembers,
 		}
 
-		err = prSaver.CreatePullRequest(pullRequest)
+		err = prSaver.CreatePullRequest(r.Context(), pullRequest, actor.FromContext(r.Context()))
 		if err != nil {
 			log.Error("failed to create PR", slog.String("op", op), slog.String("error", err.Error()))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusConflict)
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "PR_EXISTS",
-					Message: "pull_request already exist",
-				},
-			})
+			response.Error(w, err)
 			return
 		}
 
@@ -127,7 +156,8 @@ func New(log *slog.Logger, prSaver PRSeverInterface) http.HandlerFunc {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(Response{
-			PullRequest: pullRequest,
+			PullRequest:       pullRequest,
+			FallbackReviewers: selection.Fallback,
 		})
 
 	}
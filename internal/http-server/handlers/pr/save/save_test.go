@@ -0,0 +1,156 @@
+package PrSave
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/internal/assignment"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newHandler - обработчик поверх хранилища в памяти с командой backend:
+// автор u1 и ревьюеры u2, u3, u4 (стратегия least_loaded, 2 ревьюера на PR)
+func newHandler(t *testing.T, minReviewers int, members ...models.TeamMember) (http.HandlerFunc, *memory.Storage) {
+	t.Helper()
+
+	if len(members) == 0 {
+		members = memorytest.Members(4)
+	}
+	store := memorytest.New(t, models.Team{
+		AssignmentStrategy: assignment.StrategyLeastLoaded,
+		MinReviewers:       minReviewers,
+		MaxReviewers:       2,
+		Members:            members,
+	})
+
+	return New(memorytest.Logger(), store, memorytest.Picker(t, store)), store
+}
+
+func create(t *testing.T, h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body)))
+	return rec
+}
+
+func TestNewAssignsReviewers(t *testing.T) {
+	h, _ := newHandler(t, 1)
+
+	rec := create(t, h, `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.PullRequest.Status != models.StatusOpen {
+		t.Errorf("status = %q, want %q", resp.PullRequest.Status, models.StatusOpen)
+	}
+	// При равной нагрузке least_loaded выбирает по user_id
+	if got := strings.Join(resp.PullRequest.AssignedReviewers, ","); got != "u2,u3" {
+		t.Errorf("reviewers = %s, want u2,u3", got)
+	}
+}
+
+func TestNewLeastLoaded(t *testing.T) {
+	h, store := newHandler(t, 1)
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		rec := create(t, h, `{"pull_request_id":"`+id+`","pull_request_name":"Change","author_id":"u1"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("%s: status = %d, want %d: %s", id, rec.Code, http.StatusCreated, rec.Body)
+		}
+	}
+
+	// 6 назначений на трёх ревьюеров распределяются поровну
+	loads, err := store.GetOpenReviewLoad(context.Background(), []string{"u2", "u3", "u4"})
+	if err != nil {
+		t.Fatalf("failed to get loads: %v", err)
+	}
+	for _, reviewer := range []string{"u2", "u3", "u4"} {
+		if loads[reviewer] != 2 {
+			t.Errorf("load of %s = %d, want 2 (loads: %v)", reviewer, loads[reviewer], loads)
+		}
+	}
+}
+
+func TestNewNotEnoughReviewers(t *testing.T) {
+	h, _ := newHandler(t, 2,
+		models.TeamMember{UserID: "u1", UserName: "Alice", IsActive: true},
+		models.TeamMember{UserID: "u2", UserName: "Bob", IsActive: true},
+		models.TeamMember{UserID: "u3", UserName: "Carol", IsActive: false},
+	)
+
+	rec := create(t, h, `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != "NOT_ENOUGH_REVIEWERS" {
+		t.Errorf("code = %q, want NOT_ENOUGH_REVIEWERS", resp.Error.Code)
+	}
+}
+
+func TestNewNoCandidate(t *testing.T) {
+	h, _ := newHandler(t, 0,
+		models.TeamMember{UserID: "u1", UserName: "Alice", IsActive: true},
+		models.TeamMember{UserID: "u2", UserName: "Bob", IsActive: false},
+	)
+
+	rec := create(t, h, `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != "NO_CANDIDATE" {
+		t.Errorf("code = %q, want NO_CANDIDATE", resp.Error.Code)
+	}
+}
+
+func TestNewAuthorNotFound(t *testing.T) {
+	h, _ := newHandler(t, 1)
+
+	for _, draft := range []string{"false", "true"} {
+		rec := create(t, h, `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"ghost","draft":`+draft+`}`)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("draft=%s: status = %d, want %d: %s", draft, rec.Code, http.StatusNotFound, rec.Body)
+		}
+
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Error.Message != "author not found" {
+			t.Errorf("draft=%s: message = %q, want %q", draft, resp.Error.Message, "author not found")
+		}
+	}
+}
+
+func TestNewDuplicate(t *testing.T) {
+	h, _ := newHandler(t, 1)
+
+	body := `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`
+	if rec := create(t, h, body); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	if rec := create(t, h, body); rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+}
@@ -0,0 +1,152 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"main.go/internal/assignment"
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Request - структура запроса
+type Request struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+// Response - структура ответа
+type Response struct {
+	PullRequest models.PullRequest `json:"pr"`
+	// FallbackReviewers - ревьюеры из резервных команд: user_id -> имя команды
+	FallbackReviewers map[string]string `json:"fallbackreviewers,omitempty"`
+	// Reassignment - замена ревьюеров, деактивированных, пока PR был закрыт (только если такие были)
+	Reassignment *models.ReassignmentReport `json:"reassignment,omitempty"`
+}
+
+// PRStatusChangerInterface - интерфейс для смены статуса PR
+type PRStatusChangerInterface interface {
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
+	ChangePullRequestStatus(ctx context.Context, pullRequestID string, transition models.Transition, reviewers []string, planner storage.ReassignmentPlanner, actor string) (*models.PullRequest, *models.ReassignmentReport, error)
+}
+
+// ReviewerPicker - подбор ревьюеров по стратегии команды и замена неактивных
+type ReviewerPicker interface {
+	Pick(ctx context.Context, team models.Team, pr models.PullRequest, exclude []string, n int) (*assignment.Selection, error)
+	PlanReassignments(ctx context.Context, reader storage.PlanReader, prs []models.PullRequest, leaving []string) ([]models.Reassignment, error)
+}
+
+// New создаёт handler перехода жизненного цикла PR (POST /pullRequest/ready, /close, /reopen).
+// Если PR открывается и ревьюеров на нём нет, они подбираются так же, как при создании PR;
+// неактивные ревьюеры открываемого PR заменяются так же, как при деактивации пользователя
+func New(log *slog.Logger, changer PRStatusChangerInterface, picker ReviewerPicker, transition models.Transition) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.pr.status.New"
+
+		// 1. Декодируем JSON из тела запроса
+		var req Request
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "invalid request body",
+				},
+			})
+			return
+		}
+
+		if req.PullRequestID == "" {
+			log.Error("empty pull_request_id", slog.String("op", op))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "pull_request_id is required",
+				},
+			})
+			return
+		}
+
+		log.Info("changing pull request status",
+			slog.String("op", op),
+			slog.String("pr_id", req.PullRequestID),
+			slog.String("transition", transition.Name))
+
+		// 2. Получаем PR по ID
+		pullRequest, err := changer.GetPullRequestByID(r.Context(), req.PullRequestID)
+		if err != nil {
+			log.Error("failed to get PR", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 3. Проверяем, что переход допустим из текущего статуса
+		if err := transition.Check(pullRequest.Status); err != nil {
+			log.Error("invalid transition", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 4. Открываемому PR без ревьюеров подбираем их по стратегии команды автора
+		var selection assignment.Selection
+		if transition.To == models.StatusOpen && len(pullRequest.AssignedReviewers) == 0 {
+			team, err := changer.GetTeamByUser(r.Context(), pullRequest.AuthorID)
+			if err != nil {
+				log.Error("failed to get author team", slog.String("op", op), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			picked, err := picker.Pick(r.Context(), *team, *pullRequest, nil, team.MaxReviewers)
+			if err != nil {
+				log.Error("failed to pick reviewers", slog.String("op", op), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			if len(picked.Reviewers) < team.MinReviewers {
+				log.Error("not enough reviewers", slog.String("op", op),
+					slog.Int("min_reviewers", team.MinReviewers), slog.Int("available", len(picked.Reviewers)))
+				response.Error(w, &assignment.NotEnoughReviewersError{Required: team.MinReviewers, Available: len(picked.Reviewers)})
+				return
+			}
+
+			selection = *picked
+		}
+
+		// 5. Меняем статус (переход перепроверяется под блокировкой PR) и заменяем неактивных ревьюеров
+		pullRequest, report, err := changer.ChangePullRequestStatus(r.Context(), req.PullRequestID, transition,
+			selection.Reviewers, picker.PlanReassignments, actor.FromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to change PR status", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		if pullRequest.AssignedReviewers == nil {
+			pullRequest.AssignedReviewers = []string{}
+		}
+
+		log.Info("pull request status changed",
+			slog.String("op", op),
+			slog.String("pr_id", req.PullRequestID),
+			slog.String("status", pullRequest.Status))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			PullRequest:       *pullRequest,
+			FallbackReviewers: selection.Fallback,
+			Reassignment:      report,
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newStore - хранилище в памяти с командой из members (2 ревьюера на PR, не меньше minReviewers)
+// и PR pr-1 автора u1 со статусом и ревьюерами из pr
+func newStore(t *testing.T, minReviewers int, members []models.TeamMember, pr models.PullRequest) *memory.Storage {
+	t.Helper()
+
+	pr.PullRequestID, pr.PullRequestName, pr.AuthorID = "pr-1", "Add search", "u1"
+	return memorytest.New(t, models.Team{MinReviewers: minReviewers, MaxReviewers: 2, Members: members}, pr)
+}
+
+func change(t *testing.T, store *memory.Storage, transition models.Transition) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+
+	h := New(memorytest.Logger(), store, memorytest.Picker(t, store), transition)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/pullRequest/"+transition.Name, strings.NewReader(`{"pull_request_id":"pr-1"}`)))
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func TestNewReadyAssignsReviewers(t *testing.T) {
+	store := newStore(t, 1, memorytest.Members(4), models.PullRequest{Status: models.StatusDraft, AssignedReviewers: []string{}})
+
+	rec, resp := change(t, store, models.TransitionReady)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if resp.PullRequest.Status != models.StatusOpen {
+		t.Errorf("status = %q, want %q", resp.PullRequest.Status, models.StatusOpen)
+	}
+	if got := strings.Join(resp.PullRequest.AssignedReviewers, ","); got != "u2,u3" {
+		t.Errorf("reviewers = %s, want u2,u3", got)
+	}
+}
+
+func TestNewReadyNotEnoughReviewers(t *testing.T) {
+	store := newStore(t, 2, memorytest.Members(2), models.PullRequest{Status: models.StatusDraft, AssignedReviewers: []string{}})
+
+	rec, _ := change(t, store, models.TransitionReady)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != "NOT_ENOUGH_REVIEWERS" {
+		t.Errorf("code = %q, want NOT_ENOUGH_REVIEWERS", resp.Error.Code)
+	}
+
+	pr, err := store.GetPullRequestByID(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+	if pr.Status != models.StatusDraft {
+		t.Errorf("status after rejected ready = %q, want %q", pr.Status, models.StatusDraft)
+	}
+}
+
+func TestNewReopenReplacesInactiveReviewers(t *testing.T) {
+	store := newStore(t, 1, memorytest.Members(4), models.PullRequest{Status: models.StatusOpen, AssignedReviewers: []string{"u2", "u3"}})
+
+	if rec, _ := change(t, store, models.TransitionClose); rec.Code != http.StatusOK {
+		t.Fatalf("close: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	// Пока PR закрыт, его ревьювер уходит - закрытые PR при деактивации не переназначаются
+	if _, err := store.SetUserActive(context.Background(), "u2", false, "test"); err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+
+	rec, resp := change(t, store, models.TransitionReopen)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reopen: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if resp.Reassignment == nil || len(resp.Reassignment.Reassigned) != 1 {
+		t.Fatalf("reassignment = %+v, want u2 replaced", resp.Reassignment)
+	}
+	if move := resp.Reassignment.Reassigned[0]; move.OldReviewerID != "u2" || move.NewReviewerID != "u4" {
+		t.Errorf("move = %+v, want u2 -> u4", move)
+	}
+
+	got := strings.Join(slices.Sorted(slices.Values(resp.PullRequest.AssignedReviewers)), ",")
+	if got != "u3,u4" {
+		t.Errorf("reviewers = %s, want u3,u4", got)
+	}
+}
+
+func TestNewReopenWithActiveReviewers(t *testing.T) {
+	store := newStore(t, 1, memorytest.Members(4), models.PullRequest{Status: models.StatusClosed, AssignedReviewers: []string{"u2", "u3"}})
+
+	rec, resp := change(t, store, models.TransitionReopen)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if resp.Reassignment != nil {
+		t.Errorf("reassignment = %+v, want none", resp.Reassignment)
+	}
+}
+
+func TestNewInvalidTransition(t *testing.T) {
+	store := newStore(t, 1, memorytest.Members(4), models.PullRequest{Status: models.StatusOpen, AssignedReviewers: []string{"u2"}})
+
+	rec, _ := change(t, store, models.TransitionReopen)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+}
@@ -0,0 +1,96 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"main.go/internal/assignment"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// mapping - статус и код ответа для ошибки
+type mapping struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+// mappings проверяются по порядку: конкретные ошибки раньше общих классов
+var mappings = []mapping{
+	{storage.ErrInvalidSettings, http.StatusBadRequest, "INVALID_REQUEST", "invalid team settings"},
+
+	{storage.ErrPRExists, http.StatusConflict, "PR_EXISTS", "pull_request already exist"},
+	{storage.ErrTeamExists, http.StatusConflict, "TEAM_EXISTS", "team already exists with same members"},
+	{storage.ErrPRMerged, http.StatusConflict, "PR_MERGED", "pull request is merged"},
+	{storage.ErrPRNotOpen, http.StatusConflict, "PR_NOT_OPEN", "pull request is not open"},
+	{storage.ErrNotAssigned, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR"},
+	{storage.ErrAlreadyAssigned, http.StatusConflict, "ALREADY_ASSIGNED", "reviewer is already assigned to this PR"},
+	{storage.ErrReviewerInactive, http.StatusConflict, "REVIEWER_INACTIVE", "reviewer is not active"},
+	{storage.ErrNoCandidate, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team"},
+	{storage.ErrAPIKeyExists, http.StatusConflict, "API_KEY_EXISTS", "api key already exists"},
+	{models.ErrInvalidTransition, http.StatusConflict, "INVALID_TRANSITION", "status transition is not allowed"},
+	{assignment.ErrCandidateIsAuthor, http.StatusConflict, "REVIEWER_IS_AUTHOR", assignment.ErrCandidateIsAuthor.Error()},
+	{assignment.ErrCandidateAssigned, http.StatusConflict, "ALREADY_ASSIGNED", assignment.ErrCandidateAssigned.Error()},
+	{assignment.ErrCandidateInactive, http.StatusConflict, "REVIEWER_INACTIVE", assignment.ErrCandidateInactive.Error()},
+	{assignment.ErrCandidateNotInTeam, http.StatusConflict, "NOT_IN_TEAM", assignment.ErrCandidateNotInTeam.Error()},
+
+	{storage.ErrPRNotFound, http.StatusNotFound, "NOT_FOUND", "pull request not found"},
+	{storage.ErrAuthorNotFound, http.StatusNotFound, "NOT_FOUND", "author not found"},
+	{storage.ErrUserNotFound, http.StatusNotFound, "NOT_FOUND", "user not found"},
+	{storage.ErrTeamNotFound, http.StatusNotFound, "NOT_FOUND", "team not found"},
+	{storage.ErrAPIKeyNotFound, http.StatusNotFound, "NOT_FOUND", "api key not found"},
+	{storage.ErrNotMember, http.StatusNotFound, "NOT_FOUND", "users are not members of the team"},
+
+	{storage.ErrNotFound, http.StatusNotFound, "NOT_FOUND", "resource not found"},
+	{storage.ErrConflict, http.StatusConflict, "CONFLICT", "request conflicts with current state"},
+
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "TIMEOUT", "storage did not respond in time"},
+}
+
+// Map - HTTP-статус и тело ошибки для err. Неизвестные ошибки (в том числе ошибки БД) - 500
+func Map(err error) (int, models.ErrorDetail) {
+	var policyErr *models.MergePolicyError
+	if errors.As(err, &policyErr) {
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "MERGE_POLICY_VIOLATION",
+			Message: "pull request does not satisfy the team merge policy",
+			Details: policyErr.Unmet,
+		}
+	}
+
+	var reviewersErr *assignment.NotEnoughReviewersError
+	if errors.As(err, &reviewersErr) {
+		return http.StatusConflict, models.ErrorDetail{
+			Code:    "NOT_ENOUGH_REVIEWERS",
+			Message: reviewersErr.Error(),
+		}
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return m.status, models.ErrorDetail{Code: m.code, Message: m.message}
+		}
+	}
+
+	return http.StatusInternalServerError, models.ErrorDetail{
+		Code:    "INTERNAL_ERROR",
+		Message: "internal error",
+	}
+}
+
+// Error - записать ответ с ошибкой, выбрав статус и код по типу err
+func Error(w http.ResponseWriter, err error) {
+	status, detail := Map(err)
+	Write(w, status, detail)
+}
+
+// Write - записать ответ с ошибкой в формате API
+func Write(w http.ResponseWriter, status int, detail models.ErrorDetail) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.ErrorResponse{Error: detail})
+}
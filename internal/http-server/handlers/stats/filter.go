@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"main.go/internal/models"
+)
+
+// ParseFilter - разобрать query-параметры team_name, from и to (RFC 3339) в фильтр статистики
+func ParseFilter(r *http.Request) (models.StatsFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.StatsFilter{
+		TeamName: strings.TrimSpace(query.Get("team_name")),
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		value := strings.TrimSpace(query.Get(param.name))
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be in RFC 3339 format", param.name)
+		}
+		*param.dst = &t
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	return filter, nil
+}
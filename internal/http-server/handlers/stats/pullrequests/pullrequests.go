@@ -0,0 +1,53 @@
+package statsPullRequests
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/handlers/stats"
+	"main.go/internal/models"
+)
+
+// PullRequestStatsInterface - интерфейс для получения статистики PR
+type PullRequestStatsInterface interface {
+	GetPullRequestStats(ctx context.Context, filter models.StatsFilter) (*models.PullRequestStatsReport, error)
+}
+
+// New создаёт handler для GET /stats/pullRequests
+func New(log *slog.Logger, statsGetter PullRequestStatsInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.stats.pullrequests.New"
+
+		// 1. Разбираем фильтры: team_name, from, to
+		filter, err := stats.ParseFilter(r)
+		if err != nil {
+			log.Error("invalid stats filter", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+
+		log.Info("getting pull request stats", slog.String("op", op), slog.String("team_name", filter.TeamName))
+
+		// 2. Считаем статистику
+		report, err := statsGetter.GetPullRequestStats(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to get pull request stats", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,65 @@
+package statsReviewers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/handlers/stats"
+	"main.go/internal/models"
+)
+
+// Response - структура ответа
+type Response struct {
+	Reviewers []models.ReviewerStats `json:"reviewers"`
+}
+
+// ReviewerStatsInterface - интерфейс для получения статистики ревьюеров
+type ReviewerStatsInterface interface {
+	GetReviewerStats(ctx context.Context, filter models.StatsFilter) ([]models.ReviewerStats, error)
+}
+
+// New создаёт handler для GET /stats/reviewers
+func New(log *slog.Logger, statsGetter ReviewerStatsInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.stats.reviewers.New"
+
+		// 1. Разбираем фильтры: team_name, from, to
+		filter, err := stats.ParseFilter(r)
+		if err != nil {
+			log.Error("invalid stats filter", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+
+		log.Info("getting reviewer stats", slog.String("op", op), slog.String("team_name", filter.TeamName))
+
+		// 2. Считаем статистику
+		reviewers, err := statsGetter.GetReviewerStats(r.Context(), filter)
+		if err != nil {
+			log.Error("failed to get reviewer stats", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 3. Если ревьюеров нет, возвращаем пустой массив (а не null)
+		if reviewers == nil {
+			reviewers = []models.ReviewerStats{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			Reviewers: reviewers,
+		})
+	}
+}
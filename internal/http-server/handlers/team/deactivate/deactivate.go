@@ -0,0 +1,134 @@
+package teamDeactivate
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Request - структура запроса
+type Request struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+// Response - структура ответа
+type Response struct {
+	Users        []models.User              `json:"users"`
+	Reassignment *models.ReassignmentReport `json:"reassignment"`
+}
+
+// TeamDeactivatorInterface - интерфейс для массовой деактивации участников команды
+type TeamDeactivatorInterface interface {
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string, planner storage.ReassignmentPlanner, actor string) ([]models.User, *models.ReassignmentReport, error)
+}
+
+// ReassignmentPlanner - подбор замены уходящим ревьюерам
+type ReassignmentPlanner interface {
+	PlanReassignments(ctx context.Context, reader storage.PlanReader, prs []models.PullRequest, leaving []string) ([]models.Reassignment, error)
+}
+
+// New создаёт handler для POST /team/deactivateUsers
+func New(log *slog.Logger, deactivator TeamDeactivatorInterface, planner ReassignmentPlanner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.teamDeactivate.New"
+
+		// 1. Декодируем JSON из тела запроса
+		var req Request
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("failed to decode request", slog.String("op", op), slog.String("error", err.Error()))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "invalid request body",
+				},
+			})
+			return
+		}
+
+		// 2. Валидация - команда и хотя бы один пользователь обязательны
+		slices.Sort(req.UserIDs)
+		req.UserIDs = slices.Compact(req.UserIDs)
+		if req.TeamName == "" || len(req.UserIDs) == 0 || slices.Contains(req.UserIDs, "") {
+			log.Error("empty fields in request", slog.String("op", op))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "team_name and non-empty user_ids are required",
+				},
+			})
+			return
+		}
+
+		log.Info("deactivating team users",
+			slog.String("op", op),
+			slog.String("team_name", req.TeamName),
+			slog.Int("count", len(req.UserIDs)))
+
+		// 3. Проверяем, что команда существует и все пользователи в ней состоят
+		team, err := deactivator.GetTeam(r.Context(), req.TeamName)
+		if err != nil {
+			log.Error("failed to get team", slog.String("op", op), slog.String("team_name", req.TeamName), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		var missing []string
+		for _, userID := range req.UserIDs {
+			if !slices.ContainsFunc(team.Members, func(m models.TeamMember) bool { return m.UserID == userID }) {
+				missing = append(missing, userID)
+			}
+		}
+
+		if len(missing) > 0 {
+			log.Error("users are not team members", slog.String("op", op), slog.Any("user_ids", missing))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
+					Code:    "NOT_FOUND",
+					Message: "users are not members of the team: " + strings.Join(missing, ", "),
+				},
+			})
+			return
+		}
+
+		// 4. Деактивируем пользователей и переназначаем их открытые ревью в одной транзакции,
+		// подбирая замену под блокировкой этих PR
+		users, report, err := deactivator.DeactivateTeamUsers(r.Context(), req.TeamName, req.UserIDs, planner.PlanReassignments, actor.FromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to deactivate users", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		log.Info("team users deactivated",
+			slog.String("op", op),
+			slog.String("team_name", req.TeamName),
+			slog.Int("count", len(users)),
+			slog.Int("reassigned", len(report.Reassigned)),
+			slog.Int("no_candidate", len(report.NoCandidate)))
+
+		// 5. Возвращаем деактивированных пользователей и отчёт о переназначении
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(Response{
+			Users:        users,
+			Reassignment: report,
+		})
+	}
+}
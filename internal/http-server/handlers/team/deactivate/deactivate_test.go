@@ -0,0 +1,107 @@
+package teamDeactivate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newHandler - обработчик поверх хранилища в памяти с командой из u1..u5
+// и открытыми PR: pr-1 автора u1 (ревьюеры u2, u3) и pr-2 автора u4 (ревьюеры u2, u5)
+func newHandler(t *testing.T) (http.HandlerFunc, *memory.Storage) {
+	t.Helper()
+
+	store := memorytest.New(t, models.Team{Members: memorytest.Members(5)},
+		models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}},
+		models.PullRequest{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u4", AssignedReviewers: []string{"u2", "u5"}},
+	)
+
+	return New(memorytest.Logger(), store, memorytest.Picker(t, store)), store
+}
+
+func deactivate(t *testing.T, h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/team/deactivateUsers", strings.NewReader(body)))
+	return rec
+}
+
+func TestNewReassignsOpenReviews(t *testing.T) {
+	h, store := newHandler(t)
+
+	rec := deactivate(t, h, `{"team_name":"backend","user_ids":["u2","u3"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Users) != 2 {
+		t.Fatalf("users = %+v, want u2 and u3", resp.Users)
+	}
+	for _, user := range resp.Users {
+		if user.IsActive {
+			t.Errorf("user %s is still active", user.UserID)
+		}
+	}
+
+	// Уходящие ревьюеры не подбираются друг другу на замену: на pr-1 свободны u4 и u5,
+	// на pr-2 - только u1
+	if len(resp.Reassignment.Reassigned) != 3 || len(resp.Reassignment.NoCandidate) != 0 {
+		t.Fatalf("reassignment = %+v, want 3 moves and no missing candidates", resp.Reassignment)
+	}
+
+	want := map[string]string{"pr-1": "u4,u5", "pr-2": "u1,u5"}
+	for pr, reviewers := range want {
+		got, err := store.GetPullRequestByID(context.Background(), pr)
+		if err != nil {
+			t.Fatalf("failed to get pull request: %v", err)
+		}
+		if assigned := strings.Join(slices.Sorted(slices.Values(got.AssignedReviewers)), ","); assigned != reviewers {
+			t.Errorf("%s reviewers = %s, want %s", pr, assigned, reviewers)
+		}
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"empty users", `{"team_name":"backend","user_ids":[]}`, http.StatusBadRequest},
+		{"unknown team", `{"team_name":"frontend","user_ids":["u2"]}`, http.StatusNotFound},
+		{"not a member", `{"team_name":"backend","user_ids":["u2","ghost"]}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newHandler(t)
+
+			rec := deactivate(t, h, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			// Отклонённый запрос никого не деактивирует
+			user, err := store.GetUser(context.Background(), "u2")
+			if err != nil {
+				t.Fatalf("failed to get user: %v", err)
+			}
+			if !user.IsActive {
+				t.Errorf("u2 was deactivated by a rejected request")
+			}
+		})
+	}
+}
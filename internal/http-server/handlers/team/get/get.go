@@ -1,31 +1,18 @@
 package teamGet
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 
+	"main.go/internal/http-server/handlers/response"
 	"main.go/internal/models"
 )
 
-// Response - структура ответа (возвращаем команду)
-type Response struct {
-	Team []models.TeamMember `json:"team"` // встраиваем Team напрямую, чтобы JSON был плоским
-}
-
-// ErrorResponse - структура ответа с ошибкой
-type ErrorResponse struct {
-	Error ErrorDetail `json:"error"`
-}
-
-type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
-
 // TeamGetterInterface - интерфейс для получения команды из БД
 type TeamGetterInterface interface {
-	GetTeamMembers(teamName string) ([]models.TeamMember, error)
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
 }
 
 // New создаёт handler для GET /team/get
@@ -43,8 +30,8 @@ func New(log *slog.Logger, teamGetter TeamGetterInterface) http.HandlerFunc {
 			log.Error("teamname parameter is missing", slog.String("op", op))
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(ErrorResponse{
-				Error: ErrorDetail{
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: models.ErrorDetail{
 					Code:    "INVALID_REQUEST",
 					Message: "teamname parameter is required",
 				},
@@ -52,26 +39,20 @@ func New(log *slog.Logger, teamGetter TeamGetterInterface) http.HandlerFunc {
 			return
 		}
 
-		// 3. Получаем команду из БД
-		team, err := teamGetter.GetTeamMembers(teamName)
+		// 3. Получаем команду с настройками и участниками из БД
+		team, err := teamGetter.GetTeam(r.Context(), teamName)
 		if err != nil {
-			log.Error("team not found", slog.String("op", op), slog.String("teamname", teamName))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound) // 404
-			json.NewEncoder(w).Encode(ErrorResponse{
-				Error: ErrorDetail{
-					Code:    "NOTFOUND",
-					Message: "team not found",
-				},
-			})
+			log.Error("failed to get team", slog.String("op", op), slog.String("teamname", teamName), slog.String("error", err.Error()))
+			response.Error(w, err)
 			return
 		}
 
 		log.Info("team found", slog.String("op", op), slog.String("teamname", teamName))
 
-		// 4. Возвращаем команду
+		// 4. Возвращаем команду целиком: настройки и участников в members.
+		// Раньше ответ был массивом участников - к массиву нельзя добавить лимиты ревьюеров
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)     // 200
-		json.NewEncoder(w).Encode(&team) // возвращаем team напрямую
+		w.WriteHeader(http.StatusOK) // 200
+		json.NewEncoder(w).Encode(team)
 	}
 }
@@ -1,17 +1,30 @@
 package teamSave
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"slices"
 
+	"main.go/internal/assignment"
+	"main.go/internal/http-server/handlers/response"
 	"main.go/internal/models"
 )
 
 // Структура запроса
 type Request struct {
-	TeamName string              `json:"teamname"`
-	Members  []models.TeamMember `json:"members"`
+	TeamName           string   `json:"teamname"`
+	AssignmentStrategy string   `json:"assignmentstrategy"` // пусто - стратегия из конфига
+	MinReviewers       int      `json:"minreviewers"`       // 0 - не менять (по умолчанию 1)
+	MaxReviewers       int      `json:"maxreviewers"`       // 0 - не менять (по умолчанию 2)
+	FallbackTeams      []string `json:"fallbackteams"`      // null - не менять, [] - очистить
+	// Политика слияния (null - не менять): число одобрений (0 - без проверки), запрет слияния
+	// при запрошенных изменениях и запрет последнего одобрения от автора
+	RequiredApprovals     *int                `json:"requiredapprovals"`
+	NoChangesRequested    *bool               `json:"nochangesrequested"`
+	AuthorNotLastApprover *bool               `json:"authornotlastapprover"`
+	Members               []models.TeamMember `json:"members"`
 }
 
 // Структура ответа
@@ -20,8 +33,9 @@ type Response struct {
 }
 
 type TeamSaverInterface interface {
-	SaveTeamWithUpdate(team models.Team) (bool, error)
-	GetTeamMembers(teamName string) ([]models.TeamMember, error)
+	SaveTeamWithUpdate(ctx context.Context, team models.Team) (bool, error)
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	TeamExists(ctx context.Context, teamName string) (bool, error)
 }
 
 func New(log *slog.Logger, teamSaver TeamSaverInterface) http.HandlerFunc {
@@ -58,45 +72,117 @@ func New(log *slog.Logger, teamSaver TeamSaverInterface) http.HandlerFunc {
 			return
 		}
 
-		log.Info("saving team", slog.String("op", op), slog.String("team_name", req.TeamName))
-
-		// 3. Создаём объект Team
-		team := models.Team{
-			TeamName: req.TeamName,
-			Members:  req.Members,
-		}
-
-		// 4. Пытаемся сохранить/обновить команду
-		isNewTeam, err := teamSaver.SaveTeamWithUpdate(team)
-		if err != nil {
-			log.Error("failed to save team", slog.String("op", op), slog.String("error", err.Error()))
+		if req.AssignmentStrategy != "" && !assignment.IsKnown(req.AssignmentStrategy) {
+			log.Error("unknown assignment strategy", slog.String("op", op), slog.String("strategy", req.AssignmentStrategy))
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusConflict) // 409
+			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(models.ErrorResponse{
 				Error: models.ErrorDetail{
-					Code:    "TEAM_EXISTS",
-					Message: "team already exists with same members",
+					Code:    "INVALID_REQUEST",
+					Message: "unknown assignment strategy",
 				},
 			})
 			return
 		}
 
-		// 5. Получаем обновлённый список членов команды
-		members, err := teamSaver.GetTeamMembers(req.TeamName)
-		if err != nil {
-			log.Error("failed to get team members", slog.String("op", op), slog.String("error", err.Error()))
+		// Лимиты ревьюеров задаются только парой: 1 <= min <= max
+		if req.MinReviewers != 0 || req.MaxReviewers != 0 {
+			if req.MinReviewers < 1 || req.MaxReviewers < req.MinReviewers {
+				log.Error("invalid reviewer limits", slog.String("op", op),
+					slog.Int("min_reviewers", req.MinReviewers), slog.Int("max_reviewers", req.MaxReviewers))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(models.ErrorResponse{
+					Error: models.ErrorDetail{
+						Code:    "INVALID_REQUEST",
+						Message: "minreviewers and maxreviewers must be set together with 1 <= minreviewers <= maxreviewers",
+					},
+				})
+				return
+			}
+		}
+
+		// Одобрений не может требоваться больше, чем назначается ревьюеров
+		if req.RequiredApprovals != nil && (*req.RequiredApprovals < 0 ||
+			req.MaxReviewers != 0 && *req.RequiredApprovals > req.MaxReviewers) {
+			log.Error("invalid required approvals", slog.String("op", op), slog.Int("required_approvals", *req.RequiredApprovals))
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(models.ErrorResponse{
 				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to get team members",
+					Code:    "INVALID_REQUEST",
+					Message: "requiredapprovals must be from 0 to maxreviewers",
 				},
 			})
 			return
 		}
 
-		team.Members = members
+		// Резервные команды должны существовать, не повторяться и не совпадать с самой командой
+		for i, fallback := range req.FallbackTeams {
+			if fallback == req.TeamName || slices.Contains(req.FallbackTeams[:i], fallback) {
+				log.Error("invalid fallback team", slog.String("op", op), slog.String("fallback_team", fallback))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(models.ErrorResponse{
+					Error: models.ErrorDetail{
+						Code:    "INVALID_REQUEST",
+						Message: "fallback teams must be unique and differ from the team itself",
+					},
+				})
+				return
+			}
+
+			exists, err := teamSaver.TeamExists(r.Context(), fallback)
+			if err != nil {
+				log.Error("failed to check fallback team", slog.String("op", op), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			if !exists {
+				log.Error("fallback team not found", slog.String("op", op), slog.String("fallback_team", fallback))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(models.ErrorResponse{
+					Error: models.ErrorDetail{
+						Code:    "INVALID_REQUEST",
+						Message: "fallback team " + fallback + " not found",
+					},
+				})
+				return
+			}
+		}
+
+		log.Info("saving team", slog.String("op", op), slog.String("team_name", req.TeamName))
+
+		// 3. Создаём объект Team
+		team := models.Team{
+			TeamName:              req.TeamName,
+			AssignmentStrategy:    req.AssignmentStrategy,
+			MinReviewers:          req.MinReviewers,
+			MaxReviewers:          req.MaxReviewers,
+			FallbackTeams:         req.FallbackTeams,
+			RequiredApprovals:     req.RequiredApprovals,
+			NoChangesRequested:    req.NoChangesRequested,
+			AuthorNotLastApprover: req.AuthorNotLastApprover,
+			Members:               req.Members,
+		}
+
+		// 4. Пытаемся сохранить/обновить команду
+		isNewTeam, err := teamSaver.SaveTeamWithUpdate(r.Context(), team)
+		if err != nil {
+			log.Error("failed to save team", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 5. Получаем сохранённую команду с настройками и участниками
+		savedTeam, err := teamSaver.GetTeam(r.Context(), req.TeamName)
+		if err != nil {
+			log.Error("failed to get team", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
 
 		// 6. Определяем статус ответа
 		statusCode := http.StatusCreated // 201 по умолчанию
@@ -113,7 +199,7 @@ func New(log *slog.Logger, teamSaver TeamSaverInterface) http.HandlerFunc {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(statusCode)
 		json.NewEncoder(w).Encode(Response{
-			Team: team,
+			Team: *savedTeam,
 		})
 	}
 }
@@ -1,11 +1,14 @@
 package getreview
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 	"strings"
 
+	"main.go/internal/http-server/handlers/pagination"
+	"main.go/internal/http-server/handlers/response"
 	"main.go/internal/models"
 )
 
@@ -13,12 +16,14 @@ import (
 type Response struct {
 	UserID       string                    `json:"user_id"`
 	PullRequests []models.PullRequestShort `json:"pull_requests"`
+	// NextCursor - курсор следующей страницы (пусто, если это последняя страница)
+	NextCursor string `json:"nextcursor,omitempty"`
 }
 
 // UserReviewInterface - интерфейс для получения PR'ов пользователя
 type UserReviewInterface interface {
-	CheckUserExists(userID string) error
-	GetUserAssignedPullRequests(userID string) ([]models.PullRequestShort, error)
+	CheckUserExists(ctx context.Context, userID string) error
+	GetUserAssignedPullRequests(ctx context.Context, filter models.ReviewFilter) ([]models.PullRequestShort, *models.PageCursor, error)
 }
 
 // New создаёт handler для GET /users/getReview
@@ -43,40 +48,60 @@ func New(log *slog.Logger, userReview UserReviewInterface) http.HandlerFunc {
 			return
 		}
 
-		log.Info("getting user assigned PRs",
-			slog.String("op", op),
-			slog.String("user_id", userID))
-
-		// 3. Проверяем, что пользователь существует
-		err := userReview.CheckUserExists(userID)
-		if err != nil {
-			log.Error("user does not exist", slog.String("op", op), slog.String("error", err.Error()))
+		// Необязательные фильтр по статусу и параметры пагинации
+		status := strings.TrimSpace(r.URL.Query().Get("status"))
+		if status != "" && !models.IsPullRequestStatus(status) {
+			log.Error("invalid status in query", slog.String("op", op), slog.String("status", status))
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
+			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(models.ErrorResponse{
 				Error: models.ErrorDetail{
-					Code:    "NOT_FOUND",
-					Message: "user not found",
+					Code:    "INVALID_REQUEST",
+					Message: "status must be one of " + strings.Join(models.PullRequestStatuses, ", "),
 				},
 			})
 			return
 		}
 
-		// 4. Получаем PR'ы, где пользователь назначен ревьювером
-		pullRequests, err := userReview.GetUserAssignedPullRequests(userID)
+		limit, after, err := pagination.Parse(r)
 		if err != nil {
-			log.Error("failed to get user pull requests", slog.String("op", op), slog.String("error", err.Error()))
+			log.Error("invalid pagination params", slog.String("op", op), slog.String("error", err.Error()))
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(models.ErrorResponse{
 				Error: models.ErrorDetail{
-					Code:    "INTERNAL_ERROR",
-					Message: "failed to get pull requests",
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
 				},
 			})
 			return
 		}
 
+		log.Info("getting user assigned PRs",
+			slog.String("op", op),
+			slog.String("user_id", userID))
+
+		// 3. Проверяем, что пользователь существует
+		err = userReview.CheckUserExists(r.Context(), userID)
+		if err != nil {
+			log.Error("user does not exist", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
+		// 4. Получаем PR'ы, где пользователь назначен ревьювером
+		pullRequests, next, err := userReview.GetUserAssignedPullRequests(r.Context(), models.ReviewFilter{
+			UserID: userID,
+			Status: status,
+			Limit:  limit,
+			After:  after,
+		})
+		if err != nil {
+			log.Error("failed to get user pull requests", slog.String("op", op), slog.String("error", err.Error()))
+			response.Error(w, err)
+			return
+		}
+
 		// 5. Если PR'ов нет, возвращаем пустой массив (а не null)
 		if pullRequests == nil {
 			pullRequests = []models.PullRequestShort{}
@@ -93,6 +118,7 @@ func New(log *slog.Logger, userReview UserReviewInterface) http.HandlerFunc {
 		json.NewEncoder(w).Encode(Response{
 			UserID:       userID,
 			PullRequests: pullRequests,
+			NextCursor:   pagination.EncodeCursor(next),
 		})
 	}
 }
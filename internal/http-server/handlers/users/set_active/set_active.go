@@ -1,11 +1,15 @@
 package setactive
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
 	"main.go/internal/models"
+	"main.go/internal/storage"
 )
 
 type Request struct {
@@ -15,13 +19,21 @@ type Request struct {
 
 type Response struct {
 	User models.User `json:"user"`
+	// Reassignment - отчёт о переназначении открытых ревью (только при деактивации)
+	Reassignment *models.ReassignmentReport `json:"reassignment,omitempty"`
 }
 
 type UserUpdaterInterface interface {
-	SetUserActive(userID string, isActive bool) (*models.User, error)
+	SetUserActive(ctx context.Context, userID string, isActive bool, actor string) (*models.User, error)
+	DeactivateUser(ctx context.Context, userID string, planner storage.ReassignmentPlanner, actor string) (*models.User, *models.ReassignmentReport, error)
 }
 
-func New(log *slog.Logger, userUpdater UserUpdaterInterface) http.HandlerFunc {
+// ReassignmentPlanner - подбор замены уходящим ревьюерам
+type ReassignmentPlanner interface {
+	PlanReassignments(ctx context.Context, reader storage.PlanReader, prs []models.PullRequest, leaving []string) ([]models.Reassignment, error)
+}
+
+func New(log *slog.Logger, userUpdater UserUpdaterInterface, planner ReassignmentPlanner) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.users.set_active.New"
 
@@ -59,32 +71,46 @@ func New(log *slog.Logger, userUpdater UserUpdaterInterface) http.HandlerFunc {
 			return
 		}
 
-		// 3. Обновляем статус
-		user, err := userUpdater.SetUserActive(req.UserID, req.IsActive)
-		if err != nil {
-			log.Error("user not found", slog.String("op", op), slog.String("user_id", req.UserID))
+		// 3. Активация - просто обновляем статус
+		if req.IsActive {
+			user, err := userUpdater.SetUserActive(r.Context(), req.UserID, true, actor.FromContext(r.Context()))
+			if err != nil {
+				log.Error("failed to update user", slog.String("op", op), slog.String("user_id", req.UserID), slog.String("error", err.Error()))
+				response.Error(w, err)
+				return
+			}
+
+			log.Info("user status updated", slog.String("op", op), slog.String("user_id", req.UserID))
+
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound) // 404
-			json.NewEncoder(w).Encode(models.ErrorResponse{
-				Error: models.ErrorDetail{
-					Code:    "NOTFOUND",
-					Message: "user not found",
-				},
+			w.WriteHeader(http.StatusOK) // 200
+			json.NewEncoder(w).Encode(Response{
+				User: *user,
 			})
 			return
 		}
-		log.Info("user status updated", slog.String("op", op), slog.String("user_id", req.UserID))
-		if user == nil {
-			log.Error("user is nil after update", slog.String("op", op))
-			// обработка ошибки
+
+		// 4. Деактивация - хранилище в одной транзакции деактивирует пользователя
+		// и переназначает его открытые ревью, подбирая замену под блокировкой этих PR
+		user, report, err := userUpdater.DeactivateUser(r.Context(), req.UserID, planner.PlanReassignments, actor.FromContext(r.Context()))
+		if err != nil {
+			log.Error("failed to update user", slog.String("op", op), slog.String("user_id", req.UserID), slog.String("error", err.Error()))
+			response.Error(w, err)
 			return
 		}
 
-		// 4. Возвращаем обновлённого пользователя
+		log.Info("user deactivated",
+			slog.String("op", op),
+			slog.String("user_id", req.UserID),
+			slog.Int("reassigned", len(report.Reassigned)),
+			slog.Int("no_candidate", len(report.NoCandidate)))
+
+		// 5. Возвращаем обновлённого пользователя и отчёт о переназначении
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK) // 200
 		json.NewEncoder(w).Encode(Response{
-			User: *user,
+			User:         *user,
+			Reassignment: report,
 		})
 	}
 }
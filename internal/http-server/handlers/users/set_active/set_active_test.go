@@ -0,0 +1,143 @@
+package setactive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newHandler - обработчик поверх хранилища в памяти с командой из members
+// и открытыми PR prs
+func newHandler(t *testing.T, members []models.TeamMember, prs ...models.PullRequest) (http.HandlerFunc, *memory.Storage) {
+	t.Helper()
+
+	store := memorytest.New(t, models.Team{Members: members}, prs...)
+	return New(memorytest.Logger(), store, memorytest.Picker(t, store)), store
+}
+
+func setIsActive(t *testing.T, h http.HandlerFunc, body string) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/users/setIsActive", strings.NewReader(body)))
+
+	var resp Response
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func TestNewDeactivateReassigns(t *testing.T) {
+	h, store := newHandler(t,
+		[]models.TeamMember{
+			{UserID: "u1", UserName: "Alice", IsActive: true},
+			{UserID: "u2", UserName: "Bob", IsActive: true},
+			{UserID: "u3", UserName: "Carol", IsActive: true},
+			{UserID: "u4", UserName: "Dave", IsActive: true},
+		},
+		models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}},
+		models.PullRequest{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u2", AssignedReviewers: []string{"u3", "u4"}},
+	)
+
+	rec, resp := setIsActive(t, h, `{"user_id":"u3","is_active":false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if resp.User.IsActive {
+		t.Errorf("user is still active")
+	}
+	if resp.Reassignment == nil {
+		t.Fatalf("reassignment report is missing")
+	}
+
+	// На каждом PR u3 заменяет единственный свободный участник команды
+	want := map[string]string{"pr-1": "u4", "pr-2": "u1"}
+	if len(resp.Reassignment.Reassigned) != len(want) {
+		t.Fatalf("reassigned = %+v, want %v", resp.Reassignment.Reassigned, want)
+	}
+	for _, move := range resp.Reassignment.Reassigned {
+		if move.OldReviewerID != "u3" || move.NewReviewerID != want[move.PullRequestID] {
+			t.Errorf("move = %+v, want u3 -> %s on %s", move, want[move.PullRequestID], move.PullRequestID)
+		}
+	}
+	if len(resp.Reassignment.NoCandidate) != 0 {
+		t.Errorf("nocandidate = %+v, want empty", resp.Reassignment.NoCandidate)
+	}
+
+	assigned, err := store.IsReviewerAssigned(context.Background(), "pr-1", "u4")
+	if err != nil {
+		t.Fatalf("failed to check assignment: %v", err)
+	}
+	if !assigned {
+		t.Errorf("u4 is not assigned to pr-1 in storage")
+	}
+}
+
+func TestNewDeactivateNoCandidate(t *testing.T) {
+	h, store := newHandler(t,
+		[]models.TeamMember{
+			{UserID: "u1", UserName: "Alice", IsActive: true},
+			{UserID: "u2", UserName: "Bob", IsActive: true},
+			{UserID: "u3", UserName: "Carol", IsActive: true},
+		},
+		models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}},
+	)
+
+	rec, resp := setIsActive(t, h, `{"user_id":"u2","is_active":false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if resp.Reassignment == nil {
+		t.Fatalf("reassignment report is missing")
+	}
+	if len(resp.Reassignment.Reassigned) != 0 {
+		t.Errorf("reassigned = %+v, want empty", resp.Reassignment.Reassigned)
+	}
+	if len(resp.Reassignment.NoCandidate) != 1 || resp.Reassignment.NoCandidate[0].OldReviewerID != "u2" {
+		t.Errorf("nocandidate = %+v, want u2 on pr-1", resp.Reassignment.NoCandidate)
+	}
+
+	// Без замены ревьювер остаётся на PR
+	assigned, err := store.IsReviewerAssigned(context.Background(), "pr-1", "u2")
+	if err != nil {
+		t.Fatalf("failed to check assignment: %v", err)
+	}
+	if !assigned {
+		t.Errorf("u2 was removed from pr-1 without a replacement")
+	}
+}
+
+func TestNewActivate(t *testing.T) {
+	h, _ := newHandler(t, []models.TeamMember{{UserID: "u1", UserName: "Alice", IsActive: false}})
+
+	rec, resp := setIsActive(t, h, `{"user_id":"u1","is_active":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if !resp.User.IsActive {
+		t.Errorf("user is not active")
+	}
+	if resp.Reassignment != nil {
+		t.Errorf("reassignment = %+v, want none on activation", resp.Reassignment)
+	}
+}
+
+func TestNewUserNotFound(t *testing.T) {
+	h, _ := newHandler(t, []models.TeamMember{{UserID: "u1", UserName: "Alice", IsActive: true}})
+
+	for _, body := range []string{`{"user_id":"ghost","is_active":false}`, `{"user_id":"ghost","is_active":true}`} {
+		if rec, _ := setIsActive(t, h, body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want %d: %s", body, rec.Code, http.StatusNotFound, rec.Body)
+		}
+	}
+}
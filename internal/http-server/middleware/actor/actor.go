@@ -0,0 +1,42 @@
+package actor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Header - заголовок, в котором клиент передаёт, от чьего имени выполняется запрос
+const Header = "X-Actor-ID"
+
+// Anonymous - актор по умолчанию, если заголовок не передан
+const Anonymous = "anonymous"
+
+type ctxKey struct{}
+
+// New - middleware, сохраняющий актора запроса в контексте для журнала назначений
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			actor := strings.TrimSpace(r.Header.Get(Header))
+			if actor == "" {
+				actor = Anonymous
+			}
+			next.ServeHTTP(w, r.WithContext(WithActor(r.Context(), actor)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithActor - положить актора в контекст
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// FromContext - получить актора из контекста
+func FromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(ctxKey{}).(string); ok {
+		return actor
+	}
+	return Anonymous
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Header - заголовок с API-ключом в формате "Bearer <ключ>"
+const Header = "Authorization"
+
+const bearerPrefix = "Bearer "
+
+// keyPrefix - префикс выдаваемых ключей, чтобы их было проще найти в логах и секретах
+const keyPrefix = "rv_"
+
+// BootstrapKeyID - идентификатор ключа администратора из конфига
+const BootstrapKeyID = "bootstrap"
+
+// KeyStore - хранилище API-ключей
+type KeyStore interface {
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+}
+
+type ctxKey struct{}
+
+// New - middleware аутентификации по API-ключу: ключ ищется в хранилище по хэшу, отозванные
+// и неизвестные ключи получают 401. Ключ bootstrapKey (если задан) всегда действует как admin -
+// им выдаются первые ключи. Актором запроса всегда становится имя ключа: X-Actor-ID игнорируется,
+// иначе ключ мог бы действовать от чужого имени. При enabled = false проверка отключена, все запросы
+// выполняются с ролью admin, а актор берётся из X-Actor-ID
+func New(log *slog.Logger, store KeyStore, enabled bool, bootstrapKey string) func(next http.Handler) http.Handler {
+	bootstrapHash := ""
+	if bootstrapKey != "" {
+		bootstrapHash = HashKey(bootstrapKey)
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "http-server.middleware.auth.New"
+
+			if !enabled {
+				key := &models.APIKey{KeyID: BootstrapKeyID, Name: BootstrapKeyID, Role: models.RoleAdmin}
+				next.ServeHTTP(w, r.WithContext(WithKey(r.Context(), key)))
+				return
+			}
+
+			// 1. Достаём ключ из заголовка
+			header := r.Header.Get(Header)
+			if !strings.HasPrefix(header, bearerPrefix) || strings.TrimSpace(header[len(bearerPrefix):]) == "" {
+				unauthorized(w, "API key is required")
+				return
+			}
+			keyHash := HashKey(strings.TrimSpace(header[len(bearerPrefix):]))
+
+			// 2. Ищем ключ: сначала ключ из конфига, затем хранилище
+			var key *models.APIKey
+			if bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(keyHash), []byte(bootstrapHash)) == 1 {
+				key = &models.APIKey{KeyID: BootstrapKeyID, Name: BootstrapKeyID, Role: models.RoleAdmin}
+			} else {
+				found, err := store.GetAPIKeyByHash(r.Context(), keyHash)
+				if errors.Is(err, storage.ErrAPIKeyNotFound) {
+					log.Warn("unknown api key", slog.String("op", op))
+					unauthorized(w, "invalid API key")
+					return
+				}
+				if err != nil {
+					log.Error("failed to get api key", slog.String("op", op), slog.String("error", err.Error()))
+					response.Error(w, err)
+					return
+				}
+				key = found
+			}
+
+			if key.RevokedAt != nil {
+				log.Warn("revoked api key", slog.String("op", op), slog.String("key_id", key.KeyID))
+				unauthorized(w, "API key is revoked")
+				return
+			}
+
+			// 3. Кладём ключ в контекст; действия пишутся в журнал и ревью от имени ключа
+			ctx := actor.WithActor(WithKey(r.Context(), key), key.Name)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Require - middleware авторизации: пропускает запросы с ключами перечисленных ролей,
+// остальные получают 403. Роль admin допускается всегда
+func Require(roles ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			role := RoleFromContext(r.Context())
+			if role != models.RoleAdmin && !slices.Contains(roles, role) {
+				response.Write(w, http.StatusForbidden, models.ErrorDetail{
+					Code:    "FORBIDDEN",
+					Message: "API key role " + role + " is not allowed to call this endpoint",
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithKey - положить API-ключ запроса в контекст
+func WithKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, ctxKey{}, key)
+}
+
+// FromContext - API-ключ запроса (nil - запрос не аутентифицирован)
+func FromContext(ctx context.Context) *models.APIKey {
+	key, _ := ctx.Value(ctxKey{}).(*models.APIKey)
+	return key
+}
+
+// RoleFromContext - роль API-ключа запроса (пусто - запрос не аутентифицирован)
+func RoleFromContext(ctx context.Context) string {
+	if key := FromContext(ctx); key != nil {
+		return key.Role
+	}
+	return ""
+}
+
+// GenerateKey - новый API-ключ и его идентификатор. Ключ содержит идентификатор,
+// чтобы по ключу из секретов было видно, какую запись отзывать
+func GenerateKey() (keyID, key string, err error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	keyID = hex.EncodeToString(id)
+	return keyID, keyPrefix + keyID + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashKey - хэш, по которому ключ хранится и ищется. Ключи случайные и длинные,
+// поэтому медленный хэш с солью не нужен
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="rv-service"`)
+	response.Write(w, http.StatusUnauthorized, models.ErrorDetail{
+		Code:    "UNAUTHORIZED",
+		Message: message,
+	})
+}
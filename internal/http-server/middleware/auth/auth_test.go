@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"main.go/internal/http-server/middleware/actor"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// newStore - хранилище в памяти с ключами бота (key-bot) и отозванным ключом (key-revoked)
+func newStore(t *testing.T) *memory.Storage {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memory.New()
+
+	if _, err := store.CreateAPIKey(ctx, models.APIKey{KeyID: "bot", Name: "ci-bot", Role: models.RoleBot}, HashKey("key-bot")); err != nil {
+		t.Fatalf("failed to create api key: %v", err)
+	}
+	if _, err := store.CreateAPIKey(ctx, models.APIKey{KeyID: "revoked", Name: "old", Role: models.RoleAdmin}, HashKey("key-revoked")); err != nil {
+		t.Fatalf("failed to create api key: %v", err)
+	}
+	if _, err := store.RevokeAPIKey(ctx, "revoked", "test"); err != nil {
+		t.Fatalf("failed to revoke api key: %v", err)
+	}
+
+	return store
+}
+
+// serve - выполнить запрос через New и Require(roles); возвращает ответ, а также ключ и актора,
+// с которыми запрос дошёл до обработчика
+func serve(t *testing.T, store KeyStore, enabled bool, header string, roles ...string) (*httptest.ResponseRecorder, *models.APIKey, string) {
+	t.Helper()
+
+	var key *models.APIKey
+	var who string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, who = FromContext(r.Context()), actor.FromContext(r.Context())
+	})
+	// Как в роутере: актор из X-Actor-ID, затем аутентификация и проверка роли
+	h := actor.New()(New(memorytest.Logger(), store, enabled, "boot")(Require(roles...)(handler)))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(actor.Header, "spoofed")
+	if header != "" {
+		req.Header.Set(Header, header)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, key, who
+}
+
+func TestNewAuthenticates(t *testing.T) {
+	store := newStore(t)
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+		keyID  string
+	}{
+		{"bootstrap key", "Bearer boot", http.StatusOK, BootstrapKeyID},
+		{"stored key", "Bearer key-bot", http.StatusOK, "bot"},
+		{"missing key", "", http.StatusUnauthorized, ""},
+		{"not bearer", "Basic key-bot", http.StatusUnauthorized, ""},
+		{"unknown key", "Bearer key-ghost", http.StatusUnauthorized, ""},
+		{"revoked key", "Bearer key-revoked", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, key, _ := serve(t, store, true, tt.header, models.RoleBot)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") == "" {
+					t.Errorf("WWW-Authenticate header is missing")
+				}
+				return
+			}
+			if key == nil || key.KeyID != tt.keyID {
+				t.Errorf("key = %+v, want %s", key, tt.keyID)
+			}
+		})
+	}
+}
+
+func TestNewActorIsKeyName(t *testing.T) {
+	store := newStore(t)
+
+	// При включённой проверке X-Actor-ID игнорируется
+	if _, _, who := serve(t, store, true, "Bearer key-bot", models.RoleBot); who != "ci-bot" {
+		t.Errorf("actor = %q, want ci-bot", who)
+	}
+	// При выключенной - актор берётся из заголовка, а ключ получает роль admin
+	_, key, who := serve(t, store, false, "", models.RoleReadOnly)
+	if who != "spoofed" {
+		t.Errorf("actor without auth = %q, want spoofed", who)
+	}
+	if key == nil || key.Role != models.RoleAdmin {
+		t.Errorf("key without auth = %+v, want admin", key)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		role   string
+		roles  []string
+		status int
+	}{
+		{models.RoleAdmin, nil, http.StatusOK},
+		{models.RoleAdmin, []string{models.RoleReadOnly}, http.StatusOK},
+		{models.RoleTeamLead, nil, http.StatusForbidden},
+		{models.RoleTeamLead, []string{models.RoleTeamLead}, http.StatusOK},
+		{models.RoleBot, []string{models.RoleTeamLead, models.RoleBot}, http.StatusOK},
+		{models.RoleReadOnly, []string{models.RoleTeamLead, models.RoleBot}, http.StatusForbidden},
+		{"", []string{models.RoleReadOnly}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		h := Require(tt.roles...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		ctx := context.Background()
+		if tt.role != "" {
+			ctx = WithKey(ctx, &models.APIKey{KeyID: "k", Role: tt.role})
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("role %q with %v: status = %d, want %d", tt.role, tt.roles, rec.Code, tt.status)
+		}
+	}
+}
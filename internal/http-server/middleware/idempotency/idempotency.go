@@ -0,0 +1,182 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"main.go/internal/http-server/handlers/response"
+	"main.go/internal/http-server/middleware/auth"
+	"main.go/internal/models"
+)
+
+// Header - заголовок, в котором клиент передаёт ключ идемпотентности
+const Header = "Idempotency-Key"
+
+// maxKeyLength - ограничение длины ключа (колонка VARCHAR(255))
+const maxKeyLength = 255
+
+// Store - хранилище ключей идемпотентности
+type Store interface {
+	// ReserveIdempotencyKey - занять ключ; если он уже занят и не истёк, вернуть сохранённую запись.
+	// Незавершённый ключ, занятый раньше чем lease назад, занимается заново
+	ReserveIdempotencyKey(ctx context.Context, apiKeyID, key, requestHash string, ttl, lease time.Duration) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, apiKeyID, key string, status int, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, apiKeyID, key string) error
+}
+
+// New - middleware идемпотентности POST-запросов: повтор запроса с тем же Idempotency-Key
+// и тем же телом возвращает сохранённый ответ, с другим телом - 422.
+// Ответы 5xx не сохраняются, чтобы клиент мог повторить запрос. Ответы с Cache-Control: no-store
+// (например, с выданным секретом) тоже не сохраняются: обработчик так отказывается от хранения ответа в БД.
+// Ключ действует в пределах API-ключа запроса, поэтому middleware ставится на маршрут после auth.Require:
+// сохранённый ответ получает только тот, кто прошёл проверку роли, и только своим ключом.
+// Запрос держит ключ не дольше lease: если процесс упал посреди запроса, повтор после lease
+// занимает ключ заново вместо 409 до истечения ttl. Чтобы исходный запрос не пережил свою аренду,
+// обработчик выполняется с контекстом, отменяемым через lease
+func New(log *slog.Logger, store Store, ttl, lease time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "http-server.middleware.idempotency.New"
+
+			key := strings.TrimSpace(r.Header.Get(Header))
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKeyLength {
+				response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "Idempotency-Key is too long",
+				})
+				return
+			}
+
+			apiKeyID := ""
+			if apiKey := auth.FromContext(r.Context()); apiKey != nil {
+				apiKeyID = apiKey.KeyID
+			}
+
+			// 1. Хэш запроса: API-ключ, метод, путь и тело
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Error("failed to read request body", slog.String("op", op), slog.String("error", err.Error()))
+				response.Write(w, http.StatusBadRequest, models.ErrorDetail{
+					Code:    "INVALID_REQUEST",
+					Message: "failed to read request body",
+				})
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			sum := sha256.Sum256([]byte(apiKeyID + "\n" + r.Method + "\n" + r.URL.Path + "\n" + string(body)))
+			requestHash := hex.EncodeToString(sum[:])
+
+			// 2. Занимаем ключ или получаем результат предыдущего запроса
+			record, err := store.ReserveIdempotencyKey(r.Context(), apiKeyID, key, requestHash, ttl, lease)
+			if err != nil {
+				log.Error("failed to reserve idempotency key", slog.String("op", op), slog.String("error", err.Error()))
+				response.Write(w, http.StatusInternalServerError, models.ErrorDetail{
+					Code:    "INTERNAL_ERROR",
+					Message: "failed to check idempotency key",
+				})
+				return
+			}
+
+			if record != nil {
+				switch {
+				case record.RequestHash != requestHash:
+					log.Error("idempotency key reused with another request", slog.String("op", op), slog.String("key", key))
+					response.Write(w, http.StatusUnprocessableEntity, models.ErrorDetail{
+						Code:    "IDEMPOTENCY_KEY_MISMATCH",
+						Message: "Idempotency-Key was already used with a different request",
+					})
+				case !record.Completed:
+					response.Write(w, http.StatusConflict, models.ErrorDetail{
+						Code:    "IDEMPOTENCY_IN_PROGRESS",
+						Message: "request with this Idempotency-Key is still in progress",
+					})
+				default:
+					log.Info("replaying idempotent response", slog.String("op", op), slog.String("key", key))
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Set("Idempotent-Replayed", "true")
+					w.WriteHeader(record.ResponseStatus)
+					w.Write(record.ResponseBody)
+				}
+				return
+			}
+
+			// 3. Выполняем запрос, запоминая ответ. Если обработчик не дошёл до ответа,
+			// ответил 5xx или запретил хранить ответ - освобождаем ключ
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			// Ключ сохраняем или освобождаем и после отключения клиента, иначе он зависнет до истечения TTL
+			storeCtx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := store.ReleaseIdempotencyKey(storeCtx, apiKeyID, key); err != nil {
+					log.Error("failed to release idempotency key", slog.String("op", op), slog.String("error", err.Error()))
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(r.Context(), lease)
+			defer cancel()
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if rec.status >= http.StatusInternalServerError || noStore(rec.Header()) {
+				return
+			}
+
+			if err := store.CompleteIdempotencyKey(storeCtx, apiKeyID, key, rec.status, rec.body.Bytes()); err != nil {
+				log.Error("failed to save idempotent response", slog.String("op", op), slog.String("error", err.Error()))
+				return
+			}
+			completed = true
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// noStore - запретил ли обработчик хранить ответ (Cache-Control: no-store)
+func noStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recorder - ResponseWriter, который пишет ответ клиенту и одновременно запоминает его
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
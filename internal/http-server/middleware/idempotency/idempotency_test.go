@@ -0,0 +1,208 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main.go/internal/http-server/middleware/auth"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+	"main.go/internal/storage/memory/memorytest"
+)
+
+// counter - обработчик, отвечающий status с номером вызова в теле
+type counter struct {
+	calls  int
+	status int
+	header http.Header
+	// started и wait - если заданы, обработчик сообщает о начале и ждёт закрытия wait
+	// или отмены контекста запроса
+	started chan struct{}
+	wait    chan struct{}
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.calls++
+	if c.wait != nil {
+		c.started <- struct{}{}
+		select {
+		case <-c.wait:
+		case <-r.Context().Done():
+		}
+	}
+	for name, values := range c.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(c.status)
+	fmt.Fprintf(w, `{"call":%d}`, c.calls)
+}
+
+func send(h http.Handler, apiKeyID, key, body string) *httptest.ResponseRecorder {
+	ctx := auth.WithKey(context.Background(), &models.APIKey{KeyID: apiKeyID, Role: models.RoleAdmin})
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+	req.Header.Set(Header, key)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.Error.Code
+}
+
+func TestNewReplaysResponse(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := New(memorytest.Logger(), memory.New(), time.Hour, time.Minute)(next)
+
+	first := send(h, "k1", "key-1", `{"a":1}`)
+	second := send(h, "k1", "key-1", `{"a":1}`)
+
+	if next.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", next.calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Idempotent-Replayed header is missing")
+	}
+}
+
+func TestNewKeyMismatch(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := New(memorytest.Logger(), memory.New(), time.Hour, time.Minute)(next)
+
+	send(h, "k1", "key-1", `{"a":1}`)
+	rec := send(h, "k1", "key-1", `{"a":2}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+	}
+	if code := errorCode(t, rec); code != "IDEMPOTENCY_KEY_MISMATCH" {
+		t.Errorf("code = %q, want IDEMPOTENCY_KEY_MISMATCH", code)
+	}
+	if next.calls != 1 {
+		t.Errorf("handler called %d times, want 1", next.calls)
+	}
+}
+
+func TestNewScopedByAPIKey(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := New(memorytest.Logger(), memory.New(), time.Hour, time.Minute)(next)
+
+	send(h, "k1", "key-1", `{"a":1}`)
+	rec := send(h, "k2", "key-1", `{"a":1}`)
+
+	// Тот же Idempotency-Key другого API-ключа - новый запрос, а не повтор чужого ответа
+	if rec.Header().Get("Idempotent-Replayed") != "" || next.calls != 2 {
+		t.Errorf("request of another api key was replayed: calls = %d, body %s", next.calls, rec.Body)
+	}
+}
+
+func TestNewInProgress(t *testing.T) {
+	next := &counter{status: http.StatusCreated, started: make(chan struct{}), wait: make(chan struct{})}
+	h := New(memorytest.Logger(), memory.New(), time.Hour, time.Minute)(next)
+
+	done := make(chan struct{})
+	go func() {
+		send(h, "k1", "key-1", `{"a":1}`)
+		close(done)
+	}()
+
+	// Повтор, пока первый запрос выполняется
+	<-next.started
+	rec := send(h, "k1", "key-1", `{"a":1}`)
+	close(next.wait)
+	<-done
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+	if code := errorCode(t, rec); code != "IDEMPOTENCY_IN_PROGRESS" {
+		t.Errorf("code = %q, want IDEMPOTENCY_IN_PROGRESS", code)
+	}
+}
+
+func TestNewReleasesUnstoredResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+	}{
+		{"server error", http.StatusInternalServerError, nil},
+		{"no-store", http.StatusCreated, http.Header{"Cache-Control": {"private, no-store"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &counter{status: tt.status, header: tt.header}
+			h := New(memorytest.Logger(), memory.New(), time.Hour, time.Minute)(next)
+
+			send(h, "k1", "key-1", `{"a":1}`)
+			rec := send(h, "k1", "key-1", `{"a":1}`)
+
+			// Ответ не сохранён, повтор выполняет запрос заново
+			if next.calls != 2 || rec.Header().Get("Idempotent-Replayed") != "" {
+				t.Errorf("calls = %d, replayed = %q, want the request to run again", next.calls, rec.Header().Get("Idempotent-Replayed"))
+			}
+		})
+	}
+}
+
+// crashedStore - хранилище, в котором ключ остаётся занятым после запроса,
+// как если бы процесс упал до сохранения или освобождения ключа
+type crashedStore struct {
+	*memory.Storage
+}
+
+func (crashedStore) CompleteIdempotencyKey(context.Context, string, string, int, []byte) error {
+	return nil
+}
+
+func (crashedStore) ReleaseIdempotencyKey(context.Context, string, string) error {
+	return nil
+}
+
+func TestNewTakesOverAbandonedKey(t *testing.T) {
+	next := &counter{status: http.StatusCreated}
+	h := New(memorytest.Logger(), crashedStore{memory.New()}, time.Hour, 20*time.Millisecond)(next)
+
+	send(h, "k1", "key-1", `{"a":1}`)
+
+	if rec := send(h, "k1", "key-1", `{"a":1}`); rec.Code != http.StatusConflict {
+		t.Fatalf("status within lease = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if rec := send(h, "k1", "key-1", `{"a":1}`); rec.Code != http.StatusCreated {
+		t.Fatalf("status after lease = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	if next.calls != 2 {
+		t.Errorf("handler called %d times, want 2", next.calls)
+	}
+}
+
+func TestNewCancelsHandlerAfterLease(t *testing.T) {
+	next := &counter{status: http.StatusCreated, started: make(chan struct{}, 1), wait: make(chan struct{})}
+	h := New(memorytest.Logger(), memory.New(), time.Hour, 20*time.Millisecond)(next)
+
+	// Обработчик не дольше lease: иначе повтор мог бы занять ключ, пока исходный запрос ещё идёт
+	start := time.Now()
+	send(h, "k1", "key-1", `{"a":1}`)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("handler ran for %s, want it cancelled after the lease", elapsed)
+	}
+}
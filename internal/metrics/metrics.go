@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"main.go/internal/models"
+)
+
+// Namespace - префикс имён всех метрик сервиса
+const Namespace = "rv"
+
+// NewRegistry - реестр метрик со стандартными метриками Go-рантайма и процесса
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+type OpenPullRequestsReporter interface {
+	GetOpenPullRequestsReport(ctx context.Context) (*models.OpenPullRequestsReport, error)
+}
+
+// OpenPullRequestsGauges - доменные метрики по открытым PR. Значения пересчитываются из хранилища
+// раз в интервал, а не на каждый scrape: частые scrape не нагружают БД, а все реплики отдают одни данные
+type OpenPullRequestsGauges struct {
+	log      *slog.Logger
+	reporter OpenPullRequestsReporter
+
+	mu               sync.Mutex
+	open             *gaugeSeries
+	withoutReviewers *gaugeSeries
+	openReviews      *gaugeSeries
+	refreshedAt      prometheus.Gauge
+}
+
+// NewOpenPullRequestsGauges - метрики открытых PR, регистрируются в reg. Значения появляются после Refresh
+func NewOpenPullRequestsGauges(log *slog.Logger, reporter OpenPullRequestsReporter, reg prometheus.Registerer) *OpenPullRequestsGauges {
+	g := &OpenPullRequestsGauges{
+		log:      log,
+		reporter: reporter,
+		open: newGaugeSeries(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "open_pull_requests",
+			Help:      "Open pull requests by author team.",
+		}, "team"),
+		withoutReviewers: newGaugeSeries(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "open_pull_requests_without_reviewers",
+			Help:      "Open pull requests with no active reviewer assigned, by author team.",
+		}, "team"),
+		openReviews: newGaugeSeries(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "open_reviews",
+			Help:      "Open pull requests assigned to an active reviewer.",
+		}, "reviewer", "team"),
+		refreshedAt: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "open_pull_requests_refreshed_timestamp_seconds",
+			Help:      "Unix time of the last successful refresh of the open pull request metrics.",
+		}),
+	}
+	reg.MustRegister(g.open.vec, g.withoutReviewers.vec, g.openReviews.vec, g.refreshedAt)
+
+	return g
+}
+
+// Refresh - пересчитать метрики из хранилища. При ошибке остаются прежние значения,
+// а устаревание видно по rv_open_pull_requests_refreshed_timestamp_seconds
+func (g *OpenPullRequestsGauges) Refresh(ctx context.Context) error {
+	report, err := g.reporter.GetOpenPullRequestsReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, team := range report.Teams {
+		g.open.set(float64(team.Open), team.TeamName)
+		g.withoutReviewers.set(float64(team.WithoutReviewers), team.TeamName)
+	}
+	// В отчёте только активные ревьюеры: серии деактивированных удаляются при пересчёте
+	for _, reviewer := range report.Reviewers {
+		g.openReviews.set(float64(reviewer.OpenReviews), reviewer.UserID, reviewer.TeamName)
+	}
+	g.open.flush()
+	g.withoutReviewers.flush()
+	g.openReviews.flush()
+	g.refreshedAt.SetToCurrentTime()
+
+	return nil
+}
+
+// Run - пересчитывать метрики раз в interval, пока не отменён ctx. Первый пересчёт - сразу
+func (g *OpenPullRequestsGauges) Run(ctx context.Context, interval time.Duration) {
+	const op = "metrics.OpenPullRequestsGauges.Run"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.Refresh(ctx); err != nil && ctx.Err() == nil {
+			g.log.Error("failed to refresh open pull requests", slog.String("op", op), slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// gaugeSeries - GaugeVec, помнящий серии последнего пересчёта. Серии, которых нет в новом пересчёте
+// (деактивированный ревьювер, перешедший в другую команду пользователь), удаляются через
+// DeleteLabelValues - без Reset, при котором scrape во время пересчёта увидел бы пустые метрики
+type gaugeSeries struct {
+	vec     *prometheus.GaugeVec
+	last    map[string][]string
+	current map[string][]string
+}
+
+func newGaugeSeries(opts prometheus.GaugeOpts, labels ...string) *gaugeSeries {
+	return &gaugeSeries{
+		vec:     prometheus.NewGaugeVec(opts, labels),
+		last:    make(map[string][]string),
+		current: make(map[string][]string),
+	}
+}
+
+// set - выставить значение серии с метками labels в текущем пересчёте
+func (g *gaugeSeries) set(value float64, labels ...string) {
+	g.vec.WithLabelValues(labels...).Set(value)
+	g.current[strings.Join(labels, "\xff")] = labels
+}
+
+// flush - завершить пересчёт: удалить серии, не выставленные в нём
+func (g *gaugeSeries) flush() {
+	for key, labels := range g.last {
+		if _, ok := g.current[key]; !ok {
+			g.vec.DeleteLabelValues(labels...)
+		}
+	}
+	g.last, g.current = g.current, make(map[string][]string)
+}
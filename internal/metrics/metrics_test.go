@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+)
+
+func TestOpenPullRequestsGaugesDropsDeactivatedReviewers(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	_, err := store.SaveTeamWithUpdate(ctx, models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", UserName: "Alice", IsActive: true},
+			{UserID: "u2", UserName: "Bob", IsActive: true},
+			{UserID: "u3", UserName: "Carol", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	err = store.CreatePullRequest(ctx, models.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("failed to create pull request: %v", err)
+	}
+
+	g := NewOpenPullRequestsGauges(slog.New(slog.NewTextHandler(io.Discard, nil)), store, prometheus.NewRegistry())
+	if err := g.Refresh(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(g.openReviews.vec); got != 3 {
+		t.Errorf("open_reviews series = %d, want 3 (one per active user)", got)
+	}
+	if got := testutil.ToFloat64(g.openReviews.vec.WithLabelValues("u2", "backend")); got != 1 {
+		t.Errorf("open_reviews{reviewer=u2} = %v, want 1", got)
+	}
+
+	if _, err := store.SetUserActive(ctx, "u3", false, "test"); err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+	if err := g.Refresh(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(g.openReviews.vec); got != 2 {
+		t.Errorf("open_reviews series after deactivation = %d, want 2", got)
+	}
+}
+
+func TestOpenPullRequestsGaugesCountsPullRequestsWithoutActiveReviewers(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	_, err := store.SaveTeamWithUpdate(ctx, models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", UserName: "Alice", IsActive: true},
+			{UserID: "u2", UserName: "Bob", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+	err = store.CreatePullRequest(ctx, models.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("failed to create pull request: %v", err)
+	}
+
+	g := NewOpenPullRequestsGauges(slog.New(slog.NewTextHandler(io.Discard, nil)), store, prometheus.NewRegistry())
+	withoutReviewers := func() float64 {
+		t.Helper()
+		if err := g.Refresh(ctx); err != nil {
+			t.Fatalf("failed to refresh: %v", err)
+		}
+		return testutil.ToFloat64(g.withoutReviewers.vec.WithLabelValues("backend"))
+	}
+
+	if got := withoutReviewers(); got != 0 {
+		t.Errorf("without_reviewers = %v, want 0", got)
+	}
+
+	// Замены нет: u2 остаётся на PR неактивным, и PR застревает
+	if _, err := store.SetUserActive(ctx, "u2", false, "test"); err != nil {
+		t.Fatalf("failed to deactivate user: %v", err)
+	}
+	if got := withoutReviewers(); got != 1 {
+		t.Errorf("without_reviewers after deactivation = %v, want 1", got)
+	}
+}
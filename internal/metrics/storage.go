@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Storage - обёртка хранилища, замеряющая длительность каждого вызова по имени метода
+// и результату (ok/error)
+type Storage struct {
+	next     storage.Storage
+	duration *prometheus.HistogramVec
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+// NewStorage - обернуть хранилище и зарегистрировать гистограмму в reg
+func NewStorage(next storage.Storage, reg prometheus.Registerer) *Storage {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "storage",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of storage calls by method and result.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"method", "result"})
+	reg.MustRegister(duration)
+
+	return &Storage{next: next, duration: duration}
+}
+
+// observe - записать длительность вызова метода
+func (s *Storage) observe(method string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	s.duration.WithLabelValues(method, result).Observe(time.Since(start).Seconds())
+}
+
+// call - выполнить метод хранилища, возвращающий значение и ошибку, с замером
+func call[T any](s *Storage, method string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	v, err := fn()
+	s.observe(method, start, err)
+	return v, err
+}
+
+// exec - выполнить метод хранилища, возвращающий только ошибку, с замером
+func (s *Storage) exec(method string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	s.observe(method, start, err)
+	return err
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.exec("Ping", func() error { return s.next.Ping(ctx) })
+}
+
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	return s.exec("CheckMigrations", func() error { return s.next.CheckMigrations(ctx) })
+}
+
+func (s *Storage) Close() error {
+	return s.next.Close()
+}
+
+func (s *Storage) SaveTeamWithUpdate(ctx context.Context, team models.Team) (bool, error) {
+	return call(s, "SaveTeamWithUpdate", func() (bool, error) { return s.next.SaveTeamWithUpdate(ctx, team) })
+}
+
+func (s *Storage) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	return call(s, "TeamExists", func() (bool, error) { return s.next.TeamExists(ctx, teamName) })
+}
+
+func (s *Storage) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	return call(s, "GetTeam", func() (*models.Team, error) { return s.next.GetTeam(ctx, teamName) })
+}
+
+func (s *Storage) GetTeamByUser(ctx context.Context, userID string) (*models.Team, error) {
+	return call(s, "GetTeamByUser", func() (*models.Team, error) { return s.next.GetTeamByUser(ctx, userID) })
+}
+
+func (s *Storage) GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error) {
+	return call(s, "GetActiveMembersByTeam", func() ([]string, error) { return s.next.GetActiveMembersByTeam(ctx, teamName) })
+}
+
+func (s *Storage) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return call(s, "GetOpenReviewLoad", func() (map[string]int, error) { return s.next.GetOpenReviewLoad(ctx, userIDs) })
+}
+
+func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	return call(s, "GetUser", func() (*models.User, error) { return s.next.GetUser(ctx, userID) })
+}
+
+func (s *Storage) CheckUserExists(ctx context.Context, userID string) error {
+	return s.exec("CheckUserExists", func() error { return s.next.CheckUserExists(ctx, userID) })
+}
+
+func (s *Storage) SetUserActive(ctx context.Context, userID string, isActive bool, actor string) (*models.User, error) {
+	return call(s, "SetUserActive", func() (*models.User, error) { return s.next.SetUserActive(ctx, userID, isActive, actor) })
+}
+
+func (s *Storage) DeactivateUser(ctx context.Context, userID string, planner storage.ReassignmentPlanner, actor string) (*models.User, *models.ReassignmentReport, error) {
+	start := time.Now()
+	user, report, err := s.next.DeactivateUser(ctx, userID, planner, actor)
+	s.observe("DeactivateUser", start, err)
+	return user, report, err
+}
+
+func (s *Storage) DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string, planner storage.ReassignmentPlanner, actor string) ([]models.User, *models.ReassignmentReport, error) {
+	start := time.Now()
+	users, report, err := s.next.DeactivateTeamUsers(ctx, teamName, userIDs, planner, actor)
+	s.observe("DeactivateTeamUsers", start, err)
+	return users, report, err
+}
+
+func (s *Storage) CreatePullRequest(ctx context.Context, pr models.PullRequest, actor string) error {
+	return s.exec("CreatePullRequest", func() error { return s.next.CreatePullRequest(ctx, pr, actor) })
+}
+
+func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error) {
+	return call(s, "GetPullRequestByID", func() (*models.PullRequest, error) { return s.next.GetPullRequestByID(ctx, pullRequestID) })
+}
+
+func (s *Storage) ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequest, *models.PageCursor, error) {
+	start := time.Now()
+	prs, next, err := s.next.ListPullRequests(ctx, filter)
+	s.observe("ListPullRequests", start, err)
+	return prs, next, err
+}
+
+func (s *Storage) MergePullRequest(ctx context.Context, pullRequestID string, override *models.MergeOverride, actor string) (*models.PullRequest, error) {
+	return call(s, "MergePullRequest", func() (*models.PullRequest, error) {
+		return s.next.MergePullRequest(ctx, pullRequestID, override, actor)
+	})
+}
+
+func (s *Storage) ChangePullRequestStatus(ctx context.Context, pullRequestID string, transition models.Transition, reviewers []string, planner storage.ReassignmentPlanner, actor string) (*models.PullRequest, *models.ReassignmentReport, error) {
+	start := time.Now()
+	pr, report, err := s.next.ChangePullRequestStatus(ctx, pullRequestID, transition, reviewers, planner, actor)
+	s.observe("ChangePullRequestStatus", start, err)
+	return pr, report, err
+}
+
+func (s *Storage) ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID, newReviewerID, actor string) error {
+	return s.exec("ReassignReviewer", func() error {
+		return s.next.ReassignReviewer(ctx, pullRequestID, oldReviewerID, newReviewerID, actor)
+	})
+}
+
+func (s *Storage) IsReviewerAssigned(ctx context.Context, pullRequestID, userID string) (bool, error) {
+	return call(s, "IsReviewerAssigned", func() (bool, error) { return s.next.IsReviewerAssigned(ctx, pullRequestID, userID) })
+}
+
+func (s *Storage) SubmitReview(ctx context.Context, pullRequestID, reviewerID, decision, comment, actor string) error {
+	return s.exec("SubmitReview", func() error {
+		return s.next.SubmitReview(ctx, pullRequestID, reviewerID, decision, comment, actor)
+	})
+}
+
+func (s *Storage) GetPullRequestReviews(ctx context.Context, pullRequestID string) ([]models.Review, error) {
+	return call(s, "GetPullRequestReviews", func() ([]models.Review, error) { return s.next.GetPullRequestReviews(ctx, pullRequestID) })
+}
+
+func (s *Storage) GetUserAssignedPullRequests(ctx context.Context, filter models.ReviewFilter) ([]models.PullRequestShort, *models.PageCursor, error) {
+	start := time.Now()
+	prs, next, err := s.next.GetUserAssignedPullRequests(ctx, filter)
+	s.observe("GetUserAssignedPullRequests", start, err)
+	return prs, next, err
+}
+
+func (s *Storage) GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]models.AssignmentEvent, error) {
+	return call(s, "GetPullRequestHistory", func() ([]models.AssignmentEvent, error) {
+		return s.next.GetPullRequestHistory(ctx, pullRequestID)
+	})
+}
+
+func (s *Storage) GetReviewerStats(ctx context.Context, filter models.StatsFilter) ([]models.ReviewerStats, error) {
+	return call(s, "GetReviewerStats", func() ([]models.ReviewerStats, error) { return s.next.GetReviewerStats(ctx, filter) })
+}
+
+func (s *Storage) GetPullRequestStats(ctx context.Context, filter models.StatsFilter) (*models.PullRequestStatsReport, error) {
+	return call(s, "GetPullRequestStats", func() (*models.PullRequestStatsReport, error) {
+		return s.next.GetPullRequestStats(ctx, filter)
+	})
+}
+
+func (s *Storage) GetOpenPullRequestsReport(ctx context.Context) (*models.OpenPullRequestsReport, error) {
+	return call(s, "GetOpenPullRequestsReport", func() (*models.OpenPullRequestsReport, error) {
+		return s.next.GetOpenPullRequestsReport(ctx)
+	})
+}
+
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, apiKeyID, key, requestHash string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	return call(s, "ReserveIdempotencyKey", func() (*models.IdempotencyRecord, error) {
+		return s.next.ReserveIdempotencyKey(ctx, apiKeyID, key, requestHash, ttl, lease)
+	})
+}
+
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, apiKeyID, key string, status int, body []byte) error {
+	return s.exec("CompleteIdempotencyKey", func() error {
+		return s.next.CompleteIdempotencyKey(ctx, apiKeyID, key, status, body)
+	})
+}
+
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, apiKeyID, key string) error {
+	return s.exec("ReleaseIdempotencyKey", func() error { return s.next.ReleaseIdempotencyKey(ctx, apiKeyID, key) })
+}
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key models.APIKey, keyHash string) (*models.APIKey, error) {
+	return call(s, "CreateAPIKey", func() (*models.APIKey, error) { return s.next.CreateAPIKey(ctx, key, keyHash) })
+}
+
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return call(s, "GetAPIKeyByHash", func() (*models.APIKey, error) { return s.next.GetAPIKeyByHash(ctx, keyHash) })
+}
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, keyID, actor string) (*models.APIKey, error) {
+	return call(s, "RevokeAPIKey", func() (*models.APIKey, error) { return s.next.RevokeAPIKey(ctx, keyID, actor) })
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Роли API-ключей
+const (
+	RoleAdmin    = "admin"     // все операции, включая выдачу ключей и слияние в обход политики
+	RoleTeamLead = "team-lead" // управление командами и PR
+	RoleBot      = "bot"       // операции с PR из CI и интеграций
+	RoleReadOnly = "read-only" // только чтение
+)
+
+// Roles - допустимые роли API-ключей
+var Roles = []string{RoleAdmin, RoleTeamLead, RoleBot, RoleReadOnly}
+
+// IsRole - проверить, что роль допустима
+func IsRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// APIKey - выданный API-ключ. Сам ключ не хранится, только его хэш
+type APIKey struct {
+	KeyID     string     `json:"keyid"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	CreatedBy string     `json:"createdby"`
+	CreatedAt time.Time  `json:"createdat"`
+	RevokedAt *time.Time `json:"revokedat"`
+	RevokedBy string     `json:"revokedby,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Типы событий журнала назначений
+const (
+	EventAssign     = "assign"
+	EventReassign   = "reassign"
+	EventMerge      = "merge"
+	EventReady      = "ready"
+	EventClose      = "close"
+	EventReopen     = "reopen"
+	EventReview     = "review"
+	EventOverride   = "merge_override"
+	EventActivate   = "activate"
+	EventDeactivate = "deactivate"
+)
+
+// AssignmentEvent - запись журнала назначений (append-only)
+type AssignmentEvent struct {
+	ID            int64     `json:"id" db:"id"`
+	Type          string    `json:"type" db:"event_type"`
+	PullRequestID string    `json:"pullrequestid,omitempty" db:"pull_request_id"`
+	UserID        string    `json:"userid,omitempty" db:"user_id"`
+	Actor         string    `json:"actor" db:"actor"`
+	OldValue      string    `json:"oldvalue,omitempty" db:"old_value"`
+	NewValue      string    `json:"newvalue,omitempty" db:"new_value"`
+	Comment       string    `json:"comment,omitempty" db:"comment"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
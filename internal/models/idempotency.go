@@ -0,0 +1,13 @@
+package models
+
+// IdempotencyRecord - сохранённый результат запроса с заголовком Idempotency-Key
+type IdempotencyRecord struct {
+	// APIKeyID - ключ, которым выполнен запрос: Idempotency-Key действует только в его пределах
+	APIKeyID    string
+	Key         string
+	RequestHash string
+	// Completed - ответ уже сохранён; false - первый запрос с этим ключом ещё выполняется
+	Completed      bool
+	ResponseStatus int
+	ResponseBody   []byte
+}
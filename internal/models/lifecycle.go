@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Статусы PR
+const (
+	StatusDraft  = "DRAFT"  // черновик: ревьюеры не назначаются
+	StatusOpen   = "OPEN"   // открыт и на ревью
+	StatusMerged = "MERGED" // слит, конечное состояние
+	StatusClosed = "CLOSED" // закрыт без слияния, можно переоткрыть
+)
+
+// PullRequestStatuses - допустимые статусы PR
+var PullRequestStatuses = []string{StatusDraft, StatusOpen, StatusMerged, StatusClosed}
+
+// IsPullRequestStatus - проверить, что статус PR допустим
+func IsPullRequestStatus(status string) bool {
+	return slices.Contains(PullRequestStatuses, status)
+}
+
+// ErrInvalidTransition - переход PR в новый статус из текущего запрещён
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// Transition - переход жизненного цикла PR. Name совпадает с типом события в журнале назначений
+type Transition struct {
+	Name string
+	From []string
+	To   string
+}
+
+// Разрешённые переходы жизненного цикла PR
+var (
+	TransitionReady  = Transition{Name: EventReady, From: []string{StatusDraft}, To: StatusOpen}
+	TransitionClose  = Transition{Name: EventClose, From: []string{StatusDraft, StatusOpen}, To: StatusClosed}
+	TransitionReopen = Transition{Name: EventReopen, From: []string{StatusClosed}, To: StatusOpen}
+	TransitionMerge  = Transition{Name: EventMerge, From: []string{StatusOpen}, To: StatusMerged}
+)
+
+// Check - проверить, что переход допустим из статуса from
+func (t Transition) Check(from string) error {
+	if !slices.Contains(t.From, from) {
+		return fmt.Errorf("%w: cannot %s pull request in status %s", ErrInvalidTransition, t.Name, from)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTransitionCheck(t *testing.T) {
+	transitions := []Transition{TransitionReady, TransitionClose, TransitionReopen, TransitionMerge}
+
+	// Допустимые статусы, из которых выполняется каждый переход
+	allowed := map[string][]string{
+		EventReady:  {StatusDraft},
+		EventClose:  {StatusDraft, StatusOpen},
+		EventReopen: {StatusClosed},
+		EventMerge:  {StatusOpen},
+	}
+
+	for _, transition := range transitions {
+		for _, from := range PullRequestStatuses {
+			err := transition.Check(from)
+
+			want := slices.Contains(allowed[transition.Name], from)
+			if want && err != nil {
+				t.Errorf("%s from %s: unexpected error %v", transition.Name, from, err)
+			}
+			if !want && !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("%s from %s: error = %v, want ErrInvalidTransition", transition.Name, from, err)
+			}
+		}
+	}
+}
@@ -3,8 +3,17 @@ package models
 import "time"
 
 type Team struct {
-	TeamName string       `json:"teamname" db:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName           string `json:"teamname" db:"team_name"`
+	AssignmentStrategy string `json:"assignmentstrategy,omitempty" db:"assignment_strategy"`
+	MinReviewers       int    `json:"minreviewers" db:"min_reviewers"`
+	MaxReviewers       int    `json:"maxreviewers" db:"max_reviewers"`
+	// Политика слияния: сколько одобрений нужно (0 - без проверки), запрещать ли слияние
+	// при запрошенных изменениях и может ли последнее одобрение исходить от автора
+	RequiredApprovals     *int         `json:"requiredapprovals,omitempty" db:"required_approvals"`
+	NoChangesRequested    *bool        `json:"nochangesrequested,omitempty" db:"no_changes_requested"`
+	AuthorNotLastApprover *bool        `json:"authornotlastapprover,omitempty" db:"author_not_last_approver"`
+	FallbackTeams         []string     `json:"fallbackteams,omitempty"` // резервные команды в порядке приоритета
+	Members               []TeamMember `json:"members"`
 }
 
 type TeamMember struct {
@@ -28,6 +37,7 @@ type PullRequest struct {
 	AssignedReviewers []string   `json:"assignedreviewers"`
 	CreatedAt         *time.Time `json:"createdAt" db:"created_at"`
 	MergedAt          *time.Time `json:"mergedAt" db:"merged_at"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty" db:"closed_at"`
 }
 
 type PullRequestShort struct {
@@ -36,3 +46,17 @@ type PullRequestShort struct {
 	AuthorID        string `json:"authorid" db:"author_id"`
 	Status          string `json:"status" db:"status"`
 }
+
+// Reassignment - замена ревьювера на PR
+type Reassignment struct {
+	PullRequestID string `json:"pullrequestid"`
+	OldReviewerID string `json:"oldreviewerid"`
+	NewReviewerID string `json:"newreviewerid,omitempty"`
+	FallbackTeam  string `json:"fallbackteam,omitempty"` // резервная команда нового ревьювера
+}
+
+// ReassignmentReport - какие ревью переданы другим ревьюерам, а для каких замены не нашлось
+type ReassignmentReport struct {
+	Reassigned  []Reassignment `json:"reassigned"`
+	NoCandidate []Reassignment `json:"nocandidate"`
+}
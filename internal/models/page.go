@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// PageCursor - позиция в выдаче PR, отсортированной по (created_at, pull_request_id) по убыванию:
+// следующая страница начинается с PR, идущих строго после неё
+type PageCursor struct {
+	CreatedAt     time.Time
+	PullRequestID string
+}
+
+// PullRequestFilter - фильтр списка PR. Пустые поля не ограничивают выборку,
+// интервалы дат полуоткрытые: [from, to)
+type PullRequestFilter struct {
+	Status      string
+	AuthorID    string
+	TeamName    string // команда автора
+	ReviewerID  string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	Limit       int
+	After       *PageCursor
+}
+
+// ReviewFilter - фильтр PR, на которые назначен ревьювер. Пустой статус не ограничивает выборку
+type ReviewFilter struct {
+	UserID string
+	Status string
+	Limit  int
+	After  *PageCursor
+}
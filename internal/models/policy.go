@@ -0,0 +1,99 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Условия политики слияния
+const (
+	ConditionMinApprovals          = "min_approvals"
+	ConditionNoChangesRequested    = "no_changes_requested"
+	ConditionAuthorNotLastApprover = "author_not_last_approver"
+)
+
+// MergePolicy - политика слияния PR команды автора
+type MergePolicy struct {
+	// MinApprovals - сколько одобрений нужно для слияния (0 - без проверки)
+	MinApprovals int
+	// NoChangesRequested - запрещать слияние, пока кто-то из ревьюеров запрашивает изменения
+	NoChangesRequested bool
+	// AuthorNotLastApprover - последнее одобрение не может быть отправлено самим автором PR
+	AuthorNotLastApprover bool
+}
+
+// UnmetCondition - невыполненное условие политики слияния
+type UnmetCondition struct {
+	Condition string `json:"condition"`
+	Message   string `json:"message"`
+}
+
+// Check - условия политики, которые не выполняются для PR автора authorID с ревью reviews
+func (p MergePolicy) Check(authorID string, reviews []Review) []UnmetCondition {
+	var unmet []UnmetCondition
+
+	var approvals, changesRequested []Review
+	for _, review := range reviews {
+		switch review.State {
+		case ReviewApproved:
+			approvals = append(approvals, review)
+		case ReviewChangesRequested:
+			changesRequested = append(changesRequested, review)
+		}
+	}
+
+	if len(approvals) < p.MinApprovals {
+		unmet = append(unmet, UnmetCondition{
+			Condition: ConditionMinApprovals,
+			Message:   fmt.Sprintf("%d of %d required approvals", len(approvals), p.MinApprovals),
+		})
+	}
+
+	if p.NoChangesRequested && len(changesRequested) > 0 {
+		reviewers := make([]string, len(changesRequested))
+		for i, review := range changesRequested {
+			reviewers[i] = review.UserID
+		}
+		unmet = append(unmet, UnmetCondition{
+			Condition: ConditionNoChangesRequested,
+			Message:   "changes requested by " + strings.Join(reviewers, ", "),
+		})
+	}
+
+	if p.AuthorNotLastApprover && len(approvals) > 0 {
+		last := slices.MaxFunc(approvals, func(a, b Review) int {
+			if a.ReviewedAt == nil || b.ReviewedAt == nil {
+				return cmp.Compare(a.UserID, b.UserID)
+			}
+			return a.ReviewedAt.Compare(*b.ReviewedAt)
+		})
+		if last.ReviewedBy == authorID {
+			unmet = append(unmet, UnmetCondition{
+				Condition: ConditionAuthorNotLastApprover,
+				Message:   "the last approval was submitted by the author",
+			})
+		}
+	}
+
+	return unmet
+}
+
+// MergePolicyError - PR не удовлетворяет политике слияния
+type MergePolicyError struct {
+	Unmet []UnmetCondition
+}
+
+func (e *MergePolicyError) Error() string {
+	conditions := make([]string, len(e.Unmet))
+	for i, c := range e.Unmet {
+		conditions[i] = c.Condition
+	}
+	return "merge policy not satisfied: " + strings.Join(conditions, ", ")
+}
+
+// MergeOverride - слияние в обход политики; причина обязательна и попадает в журнал
+type MergeOverride struct {
+	Reason string
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergePolicyCheck(t *testing.T) {
+	earlier := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	approved := func(userID, by string, at time.Time) Review {
+		return Review{UserID: userID, State: ReviewApproved, ReviewedAt: &at, ReviewedBy: by}
+	}
+
+	tests := []struct {
+		name    string
+		policy  MergePolicy
+		reviews []Review
+		unmet   []string
+	}{
+		{
+			name:    "no policy",
+			reviews: []Review{{UserID: "u2", State: ReviewChangesRequested}},
+		},
+		{
+			name:    "enough approvals",
+			policy:  MergePolicy{MinApprovals: 1},
+			reviews: []Review{approved("u2", "u2", earlier), {UserID: "u3", State: ReviewPending}},
+		},
+		{
+			name:    "missing approvals",
+			policy:  MergePolicy{MinApprovals: 2},
+			reviews: []Review{approved("u2", "u2", earlier), {UserID: "u3", State: ReviewPending}},
+			unmet:   []string{ConditionMinApprovals},
+		},
+		{
+			name:    "changes requested",
+			policy:  MergePolicy{MinApprovals: 1, NoChangesRequested: true},
+			reviews: []Review{approved("u2", "u2", earlier), {UserID: "u3", State: ReviewChangesRequested}},
+			unmet:   []string{ConditionNoChangesRequested},
+		},
+		{
+			name:    "author approved last",
+			policy:  MergePolicy{AuthorNotLastApprover: true},
+			reviews: []Review{approved("u2", "u2", earlier), approved("u3", "u1", later)},
+			unmet:   []string{ConditionAuthorNotLastApprover},
+		},
+		{
+			name:    "author approved earlier",
+			policy:  MergePolicy{AuthorNotLastApprover: true},
+			reviews: []Review{approved("u2", "u1", earlier), approved("u3", "u3", later)},
+		},
+		{
+			name:    "all conditions",
+			policy:  MergePolicy{MinApprovals: 2, NoChangesRequested: true, AuthorNotLastApprover: true},
+			reviews: []Review{approved("u2", "u1", earlier), {UserID: "u3", State: ReviewChangesRequested}},
+			unmet:   []string{ConditionMinApprovals, ConditionNoChangesRequested, ConditionAuthorNotLastApprover},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmet := tt.policy.Check("u1", tt.reviews)
+
+			if len(unmet) != len(tt.unmet) {
+				t.Fatalf("unmet = %+v, want %v", unmet, tt.unmet)
+			}
+			for i, condition := range unmet {
+				if condition.Condition != tt.unmet[i] {
+					t.Errorf("unmet[%d] = %s, want %s", i, condition.Condition, tt.unmet[i])
+				}
+			}
+		})
+	}
+}
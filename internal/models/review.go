@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Состояния ревью назначенного ревьювера
+const (
+	ReviewPending          = "PENDING"
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+)
+
+// Решения ревьювера
+const (
+	DecisionApprove        = "APPROVE"
+	DecisionRequestChanges = "REQUEST_CHANGES"
+	DecisionComment        = "COMMENT" // комментарий без изменения состояния ревью
+)
+
+// ReviewStateOf - состояние ревью после решения ("" - решение не меняет состояние, false - неизвестное решение)
+func ReviewStateOf(decision string) (string, bool) {
+	switch decision {
+	case DecisionApprove:
+		return ReviewApproved, true
+	case DecisionRequestChanges:
+		return ReviewChangesRequested, true
+	case DecisionComment:
+		return "", true
+	}
+	return "", false
+}
+
+// Review - состояние ревью назначенного ревьювера
+type Review struct {
+	UserID     string     `json:"userid" db:"user_id"`
+	State      string     `json:"state" db:"review_state"`
+	ReviewedAt *time.Time `json:"reviewedAt" db:"reviewed_at"`
+	ReviewedBy string     `json:"reviewedby,omitempty" db:"reviewed_by"` // кто отправил последнее решение (актор запроса)
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// StatsFilter - фильтр статистики, пустые поля не ограничивают выборку
+type StatsFilter struct {
+	TeamName string
+	From     *time.Time // включительно
+	To       *time.Time // не включительно
+}
+
+// ReviewerStats - статистика ревьювера
+type ReviewerStats struct {
+	UserID           string `json:"userid" db:"user_id"`
+	UserName         string `json:"username" db:"user_name"`
+	TeamName         string `json:"teamname" db:"team_name"`
+	TotalAssignments int    `json:"totalassignments"`
+	OpenAssignments  int    `json:"openassignments"`
+	MergedReviews    int    `json:"mergedreviews"`
+	ReassignedAway   int    `json:"reassignedaway"`
+}
+
+// PullRequestStats - статистика PR за период
+type PullRequestStats struct {
+	Created  int            `json:"created"`  // создано за период
+	ByStatus map[string]int `json:"bystatus"` // текущие статусы PR, созданных за период
+	Merged   int            `json:"merged"`   // слито за период
+	// Время до слияния (в секундах) для PR, слитых за период; null, если слияний не было
+	MedianTimeToMergeSeconds *float64 `json:"mediantimetomergeseconds"`
+	P90TimeToMergeSeconds    *float64 `json:"p90timetomergeseconds"`
+}
+
+// TeamPullRequestStats - статистика PR авторов команды
+type TeamPullRequestStats struct {
+	TeamName string `json:"teamname"`
+	PullRequestStats
+}
+
+// AuthorPullRequestStats - статистика PR автора
+type AuthorPullRequestStats struct {
+	AuthorID string `json:"authorid"`
+	TeamName string `json:"teamname"`
+	PullRequestStats
+}
+
+// PullRequestStatsReport - статистика PR: общая и в разрезе команд и авторов
+type PullRequestStatsReport struct {
+	Total   PullRequestStats         `json:"total"`
+	Teams   []TeamPullRequestStats   `json:"teams"`
+	Authors []AuthorPullRequestStats `json:"authors"`
+}
+
+// TeamOpenPullRequests - открытые PR авторов команды
+type TeamOpenPullRequests struct {
+	TeamName         string
+	Open             int
+	WithoutReviewers int // открытые PR, на которых нет ни одного активного ревьювера
+}
+
+// ReviewerOpenReviews - число открытых PR, на которые назначен ревьювер
+type ReviewerOpenReviews struct {
+	UserID      string
+	TeamName    string
+	OpenReviews int
+}
+
+// OpenPullRequestsReport - срез открытых PR по командам и активным ревьюерам для метрик
+type OpenPullRequestsReport struct {
+	Teams     []TeamOpenPullRequests
+	Reviewers []ReviewerOpenReviews
+}
@@ -0,0 +1,1503 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Storage - хранилище в памяти процесса с той же семантикой, что и postgres.Storage.
+// Данные не переживают перезапуск; подходит для тестов и демо без БД
+type Storage struct {
+	mu sync.Mutex
+
+	teams         map[string]*team
+	users         map[string]*models.User
+	pullRequests  map[string]*pullRequest
+	reassignments []reassignment
+	events        []models.AssignmentEvent
+	idempotency   map[idempotencyScope]*idempotencyKey
+	apiKeys       map[string]*apiKey
+}
+
+// team - настройки команды (участники хранятся в users)
+type team struct {
+	name                  string
+	assignmentStrategy    string
+	minReviewers          int
+	maxReviewers          int
+	requiredApprovals     int
+	noChangesRequested    bool
+	authorNotLastApprover bool
+	fallbacks             []string
+}
+
+// pullRequest - PR с ревьюерами в порядке назначения
+type pullRequest struct {
+	models.PullRequest
+	reviewers []reviewer
+}
+
+// reviewer - назначение ревьювера на PR и состояние его ревью
+type reviewer struct {
+	userID     string
+	assignedAt time.Time
+	state      string
+	reviewedAt *time.Time
+	reviewedBy string
+}
+
+// reassignment - факт передачи ревью другому ревьюеру (для статистики)
+type reassignment struct {
+	pullRequestID string
+	oldReviewerID string
+	newReviewerID string
+	assignedAt    time.Time
+	reassignedAt  time.Time
+}
+
+// idempotencyScope - ключ идемпотентности в пределах API-ключа
+type idempotencyScope struct {
+	apiKeyID string
+	key      string
+}
+
+// idempotencyKey - занятый ключ идемпотентности
+type idempotencyKey struct {
+	record     models.IdempotencyRecord
+	reservedAt time.Time
+	expiresAt  time.Time
+}
+
+// apiKey - выданный API-ключ и хэш, по которому он ищется
+type apiKey struct {
+	models.APIKey
+	hash string
+}
+
+// New - пустое хранилище в памяти
+func New() *Storage {
+	return &Storage{
+		teams:        make(map[string]*team),
+		users:        make(map[string]*models.User),
+		pullRequests: make(map[string]*pullRequest),
+		idempotency:  make(map[idempotencyScope]*idempotencyKey),
+		apiKeys:      make(map[string]*apiKey),
+	}
+}
+
+// Ping - хранилище в памяти всегда доступно
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+// CheckMigrations - у хранилища в памяти нет схемы, которую нужно мигрировать
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	return nil
+}
+
+// Close - у хранилища в памяти нет ресурсов, которые нужно освобождать
+func (s *Storage) Close() error {
+	return nil
+}
+
+// recordEvents - дописать события в журнал назначений
+func (s *Storage) recordEvents(events ...models.AssignmentEvent) {
+	now := time.Now()
+	for _, event := range events {
+		event.ID = int64(len(s.events) + 1)
+		event.CreatedAt = now
+		s.events = append(s.events, event)
+	}
+}
+
+// activityEvent - событие изменения флага активности пользователя
+func activityEvent(userID, actor string, wasActive, isActive bool) models.AssignmentEvent {
+	eventType := models.EventDeactivate
+	if isActive {
+		eventType = models.EventActivate
+	}
+
+	return models.AssignmentEvent{
+		Type:     eventType,
+		UserID:   userID,
+		Actor:    actor,
+		OldValue: strconv.FormatBool(wasActive),
+		NewValue: strconv.FormatBool(isActive),
+	}
+}
+
+// SaveTeam - сохранение команды с участниками, принимает структуру team
+func (s *Storage) SaveTeam(ctx context.Context, t models.Team) error {
+	const op = "storage.memory.SaveTeam"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[t.TeamName]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTeamExists)
+	}
+	for _, member := range t.Members {
+		if _, ok := s.users[member.UserID]; ok {
+			return fmt.Errorf("%s: user %s already exists", op, member.UserID)
+		}
+	}
+
+	s.teams[t.TeamName] = newTeam(t.TeamName)
+	for _, member := range t.Members {
+		s.users[member.UserID] = &models.User{
+			UserID:   member.UserID,
+			UserName: member.UserName,
+			TeamName: t.TeamName,
+			IsActive: member.IsActive,
+		}
+	}
+
+	return nil
+}
+
+// newTeam - команда с настройками по умолчанию (как у новой строки teams)
+func newTeam(name string) *team {
+	return &team{name: name, minReviewers: 1, maxReviewers: 2}
+}
+
+// GetTeamMembers - получить членов команды
+func (s *Storage) GetTeamMembers(ctx context.Context, teamName string) ([]models.TeamMember, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.teamMembers(teamName), nil
+}
+
+// teamMembers - участники команды по возрастанию user_id
+func (s *Storage) teamMembers(teamName string) []models.TeamMember {
+	var members []models.TeamMember
+	for _, user := range s.users {
+		if user.TeamName == teamName {
+			members = append(members, models.TeamMember{UserID: user.UserID, UserName: user.UserName, IsActive: user.IsActive})
+		}
+	}
+	slices.SortFunc(members, func(a, b models.TeamMember) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return members
+}
+
+// TeamExists - проверить, существует ли команда
+func (s *Storage) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.teams[teamName]
+	return ok, nil
+}
+
+// GetTeam - получить команду с настройками и участниками
+func (s *Storage) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	const op = "storage.memory.GetTeam"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.teams[teamName]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTeamNotFound)
+	}
+
+	result := t.model()
+	result.Members = s.teamMembers(teamName)
+	return &result, nil
+}
+
+// GetTeamByUser - получить команду пользователя с её настройками (без списка участников)
+func (s *Storage) GetTeamByUser(ctx context.Context, userID string) (*models.Team, error) {
+	const op = "storage.memory.GetTeamByUser"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, err := s.teamByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return team, nil
+}
+
+// teamByUser - команда пользователя с настройками
+func (s *Storage) teamByUser(userID string) (*models.Team, error) {
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	t, ok := s.teams[user.TeamName]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	result := t.model()
+	return &result, nil
+}
+
+// model - настройки команды в виде models.Team (без участников)
+func (t *team) model() models.Team {
+	requiredApprovals := t.requiredApprovals
+	noChangesRequested := t.noChangesRequested
+	authorNotLastApprover := t.authorNotLastApprover
+
+	return models.Team{
+		TeamName:              t.name,
+		AssignmentStrategy:    t.assignmentStrategy,
+		MinReviewers:          t.minReviewers,
+		MaxReviewers:          t.maxReviewers,
+		RequiredApprovals:     &requiredApprovals,
+		NoChangesRequested:    &noChangesRequested,
+		AuthorNotLastApprover: &authorNotLastApprover,
+		FallbackTeams:         slices.Clone(t.fallbacks),
+	}
+}
+
+// SaveTeamWithUpdate - создать команду или обновить членов
+func (s *Storage) SaveTeamWithUpdate(ctx context.Context, t models.Team) (bool, error) {
+	const op = "storage.memory.SaveTeamWithUpdate"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Все проверки выполняются до изменений, чтобы при ошибке ничего не менялось
+	current, exists := s.teams[t.TeamName]
+	if !exists {
+		current = newTeam(t.TeamName)
+	}
+
+	// Переданные настройки команды (пустые значения оставляют текущие)
+	updated := *current
+	updated.assignmentStrategy = cmp.Or(t.AssignmentStrategy, current.assignmentStrategy)
+	updated.minReviewers = cmp.Or(t.MinReviewers, current.minReviewers)
+	updated.maxReviewers = cmp.Or(t.MaxReviewers, current.maxReviewers)
+	if t.RequiredApprovals != nil {
+		updated.requiredApprovals = *t.RequiredApprovals
+	}
+	if t.NoChangesRequested != nil {
+		updated.noChangesRequested = *t.NoChangesRequested
+	}
+	if t.AuthorNotLastApprover != nil {
+		updated.authorNotLastApprover = *t.AuthorNotLastApprover
+	}
+
+	if updated.minReviewers < 1 || updated.maxReviewers < updated.minReviewers {
+		return false, fmt.Errorf("%s: %w: teams_reviewers_check", op, storage.ErrInvalidSettings)
+	}
+	if updated.requiredApprovals < 0 || updated.requiredApprovals > updated.maxReviewers {
+		return false, fmt.Errorf("%s: %w: teams_required_approvals_check", op, storage.ErrInvalidSettings)
+	}
+
+	// Если передан список резервных команд (в том числе пустой) - заменяем текущий
+	if t.FallbackTeams != nil {
+		for _, fallback := range t.FallbackTeams {
+			if _, ok := s.teams[fallback]; !ok || fallback == t.TeamName {
+				return false, fmt.Errorf("%s: failed to add fallback team %s", op, fallback)
+			}
+		}
+		updated.fallbacks = slices.Clone(t.FallbackTeams)
+	}
+
+	hasNewSettings := updated.assignmentStrategy != current.assignmentStrategy ||
+		updated.minReviewers != current.minReviewers ||
+		updated.maxReviewers != current.maxReviewers ||
+		updated.requiredApprovals != current.requiredApprovals ||
+		updated.noChangesRequested != current.noChangesRequested ||
+		updated.authorNotLastApprover != current.authorNotLastApprover ||
+		!slices.Equal(updated.fallbacks, current.fallbacks)
+
+	// Проверяем, есть ли новые члены
+	hasNewMembers := false
+	for _, member := range t.Members {
+		if user, ok := s.users[member.UserID]; !ok || user.TeamName != t.TeamName {
+			hasNewMembers = true
+			break
+		}
+	}
+
+	// Если нет новых членов, настройки не изменились и команда уже существует → ошибка
+	if exists && !hasNewMembers && !hasNewSettings {
+		return false, fmt.Errorf("%s: %w", op, storage.ErrTeamExists)
+	}
+
+	s.teams[t.TeamName] = &updated
+
+	// Добавляем только новых членов (пользователь из другой команды переходит в эту)
+	for _, member := range t.Members {
+		if user, ok := s.users[member.UserID]; ok && user.TeamName == t.TeamName {
+			continue
+		}
+		s.users[member.UserID] = &models.User{
+			UserID:   member.UserID,
+			UserName: member.UserName,
+			TeamName: t.TeamName,
+			IsActive: member.IsActive,
+		}
+	}
+
+	return !exists, nil
+}
+
+// SetUserActive - меняет флаг активности пользователя по id
+func (s *Storage) SetUserActive(ctx context.Context, userID string, isActive bool, actor string) (*models.User, error) {
+	const op = "storage.memory.SetUserActive"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	wasActive := user.IsActive
+	user.IsActive = isActive
+	if wasActive != isActive {
+		s.recordEvents(activityEvent(userID, actor, wasActive, isActive))
+	}
+
+	result := *user
+	return &result, nil
+}
+
+// DeactivateUser - деактивирует пользователя и переназначает его открытые ревью.
+// Замены подбирает planner под блокировкой хранилища
+func (s *Storage) DeactivateUser(ctx context.Context, userID string, planner storage.ReassignmentPlanner, actor string) (*models.User, *models.ReassignmentReport, error) {
+	const op = "storage.memory.DeactivateUser"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	prs, moves, err := s.planReassignments(ctx, []string{userID}, planner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.IsActive {
+		user.IsActive = false
+		s.recordEvents(activityEvent(userID, actor, true, false))
+	}
+	applied := s.applyReassignments(moves, actor)
+
+	result := *user
+	return &result, storage.NewReassignmentReport(prs, []string{userID}, applied), nil
+}
+
+// lockedReader - storage.PlanReader для planner, вызванного под s.mu
+type lockedReader struct {
+	s *Storage
+}
+
+func (r lockedReader) GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error) {
+	return r.s.activeMembers(teamName), nil
+}
+
+func (r lockedReader) GetTeamByUser(ctx context.Context, userID string) (*models.Team, error) {
+	return r.s.teamByUser(userID)
+}
+
+func (r lockedReader) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return r.s.openReviewLoad(userIDs), nil
+}
+
+// planReassignments - открытые PR уходящих ревьюеров (leaving) и замены для них от planner.
+// Вызывается под s.mu до изменений, чтобы ошибка плана ничего не меняла
+func (s *Storage) planReassignments(ctx context.Context, leaving []string, planner storage.ReassignmentPlanner) ([]models.PullRequest, []models.Reassignment, error) {
+	prs := s.openReviewsOfUsers(leaving)
+
+	moves, err := planner(ctx, lockedReader{s: s}, prs, leaving)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkReassignments(moves, leaving); err != nil {
+		return nil, nil, err
+	}
+
+	return prs, moves, nil
+}
+
+// checkReassignments - проверить, что замены можно применить: новые ревьюеры активны
+// (кроме уходящих, которых ещё не деактивировали) и ещё не назначены на PR
+func (s *Storage) checkReassignments(moves []models.Reassignment, leaving []string) error {
+	for _, move := range moves {
+		user, ok := s.users[move.NewReviewerID]
+		if !ok || !user.IsActive || slices.Contains(leaving, move.NewReviewerID) {
+			return storage.ErrReviewerInactive
+		}
+
+		pr, ok := s.pullRequests[move.PullRequestID]
+		if !ok || pr.Status != models.StatusOpen || pr.reviewerIndex(move.OldReviewerID) < 0 {
+			continue
+		}
+		if pr.reviewerIndex(move.NewReviewerID) >= 0 {
+			return storage.ErrAlreadyAssigned
+		}
+	}
+
+	return nil
+}
+
+// applyReassignments - заменить ревьюеров на открытых PR и вернуть применённые замены.
+// Если PR уже не OPEN или старый ревьювер снят, замена пропускается
+func (s *Storage) applyReassignments(moves []models.Reassignment, actor string) []models.Reassignment {
+	var applied []models.Reassignment
+	for _, move := range moves {
+		pr, ok := s.pullRequests[move.PullRequestID]
+		if !ok || pr.Status != models.StatusOpen {
+			continue
+		}
+		if _, ok := s.replaceReviewer(pr, move.OldReviewerID, move.NewReviewerID); !ok {
+			continue
+		}
+		s.recordEvents(models.AssignmentEvent{
+			Type:          models.EventReassign,
+			PullRequestID: move.PullRequestID,
+			Actor:         actor,
+			OldValue:      move.OldReviewerID,
+			NewValue:      move.NewReviewerID,
+		})
+		applied = append(applied, move)
+	}
+
+	return applied
+}
+
+// replaceReviewer - заменить ревьювера на PR и записать переназначение для статистики.
+// Возвращает false, если старый ревьювер не назначен
+func (s *Storage) replaceReviewer(pr *pullRequest, oldReviewerID, newReviewerID string) (time.Time, bool) {
+	i := pr.reviewerIndex(oldReviewerID)
+	if i < 0 {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	assignedAt := pr.reviewers[i].assignedAt
+	pr.reviewers = slices.Delete(pr.reviewers, i, i+1)
+	pr.reviewers = append(pr.reviewers, newReviewer(newReviewerID, now))
+	s.reassignments = append(s.reassignments, reassignment{
+		pullRequestID: pr.PullRequestID,
+		oldReviewerID: oldReviewerID,
+		newReviewerID: newReviewerID,
+		assignedAt:    assignedAt,
+		reassignedAt:  now,
+	})
+
+	return assignedAt, true
+}
+
+// DeactivateTeamUsers - деактивирует участников команды и переназначает их открытые ревью.
+// Замены подбирает planner под блокировкой хранилища. Если кто-то из пользователей
+// не состоит в команде, ничего не меняется
+func (s *Storage) DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string, planner storage.ReassignmentPlanner, actor string) ([]models.User, *models.ReassignmentReport, error) {
+	const op = "storage.memory.DeactivateTeamUsers"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]*models.User, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, ok := s.users[userID]
+		if !ok || user.TeamName != teamName || slices.Contains(members, user) {
+			return nil, nil, fmt.Errorf("%s: %w: team %s", op, storage.ErrNotMember, teamName)
+		}
+		members = append(members, user)
+	}
+
+	prs, moves, err := s.planReassignments(ctx, userIDs, planner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	users := make([]models.User, 0, len(members))
+	for _, user := range members {
+		if user.IsActive {
+			user.IsActive = false
+			s.recordEvents(activityEvent(user.UserID, actor, true, false))
+		}
+		users = append(users, *user)
+	}
+	applied := s.applyReassignments(moves, actor)
+
+	return users, storage.NewReassignmentReport(prs, userIDs, applied), nil
+}
+
+// GetUser - получить пользователя по id
+func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	const op = "storage.memory.GetUser"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	result := *user
+	return &result, nil
+}
+
+// CreatePullRequest - создать PR и назначить ревьюеров. Возвращает storage.ErrPRExists,
+// если PR с таким id уже есть, storage.ErrAuthorNotFound, если автора нет,
+// и storage.ErrReviewerInactive, если кого-то из ревьюеров уже деактивировали
+func (s *Storage) CreatePullRequest(ctx context.Context, pr models.PullRequest, actor string) error {
+	const op = "storage.memory.CreatePullRequest"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pullRequests[pr.PullRequestID]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrPRExists)
+	}
+
+	if _, ok := s.users[pr.AuthorID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrAuthorNotFound)
+	}
+
+	for i, reviewerID := range pr.AssignedReviewers {
+		if user, ok := s.users[reviewerID]; !ok || !user.IsActive {
+			return fmt.Errorf("%s: %w", op, storage.ErrReviewerInactive)
+		}
+		if slices.Contains(pr.AssignedReviewers[:i], reviewerID) {
+			return fmt.Errorf("%s: failed to add reviewers: duplicate reviewer %s", op, reviewerID)
+		}
+	}
+
+	now := time.Now()
+	created := &pullRequest{PullRequest: models.PullRequest{
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorID:        pr.AuthorID,
+		Status:          cmp.Or(pr.Status, models.StatusOpen),
+		CreatedAt:       &now,
+	}}
+
+	events := make([]models.AssignmentEvent, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		created.reviewers = append(created.reviewers, newReviewer(reviewerID, now))
+		events = append(events, models.AssignmentEvent{
+			Type:          models.EventAssign,
+			PullRequestID: pr.PullRequestID,
+			Actor:         actor,
+			NewValue:      reviewerID,
+		})
+	}
+
+	s.pullRequests[pr.PullRequestID] = created
+	s.recordEvents(events...)
+
+	return nil
+}
+
+// newReviewer - только что назначенный ревьювер
+func newReviewer(userID string, assignedAt time.Time) reviewer {
+	return reviewer{userID: userID, assignedAt: assignedAt, state: models.ReviewPending}
+}
+
+// reviewerIndex - позиция ревьювера в списке назначенных или -1
+func (pr *pullRequest) reviewerIndex(userID string) int {
+	return slices.IndexFunc(pr.reviewers, func(r reviewer) bool {
+		return r.userID == userID
+	})
+}
+
+// model - копия PR со списком ревьюеров в порядке назначения
+func (pr *pullRequest) model() models.PullRequest {
+	result := pr.PullRequest
+	result.AssignedReviewers = make([]string, 0, len(pr.reviewers))
+	for _, r := range pr.reviewers {
+		result.AssignedReviewers = append(result.AssignedReviewers, r.userID)
+	}
+
+	return result
+}
+
+// GetActiveMembersByTeam - получить активных участников команды
+func (s *Storage) GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.activeMembers(teamName), nil
+}
+
+// activeMembers - активные участники команды по возрастанию user_id
+func (s *Storage) activeMembers(teamName string) []string {
+	var members []string
+	for _, member := range s.teamMembers(teamName) {
+		if member.IsActive {
+			members = append(members, member.UserID)
+		}
+	}
+
+	return members
+}
+
+// GetOpenReviewLoad - количество назначений на открытые PR для каждого из пользователей
+func (s *Storage) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.openReviewLoad(userIDs), nil
+}
+
+// openReviewLoad - количество назначений на открытые PR по user_id
+func (s *Storage) openReviewLoad(userIDs []string) map[string]int {
+	loads := make(map[string]int, len(userIDs))
+	for _, pr := range s.pullRequests {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		for _, r := range pr.reviewers {
+			if slices.Contains(userIDs, r.userID) {
+				loads[r.userID]++
+			}
+		}
+	}
+
+	return loads
+}
+
+// GetPullRequestReviewers - получить список ревьюеров PR
+func (s *Storage) GetPullRequestReviewers(ctx context.Context, pullRequestID string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return nil, nil
+	}
+
+	return pr.model().AssignedReviewers, nil
+}
+
+// MergePullRequest - пометить PR как MERGED (идемпотентная операция: повторный вызов
+// не меняет merged_at и не пишет событие в журнал). Перед слиянием проверяется политика
+// команды автора; с override PR сливается в обход неё, а нарушенные условия и причина пишутся в журнал
+func (s *Storage) MergePullRequest(ctx context.Context, pullRequestID string, override *models.MergeOverride, actor string) (*models.PullRequest, error) {
+	const op = "storage.memory.MergePullRequest"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if pr.Status == models.StatusMerged {
+		result := pr.model()
+		return &result, nil
+	}
+
+	if err := models.TransitionMerge.Check(pr.Status); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Проверяем политику слияния команды автора до изменения статуса
+	var policy models.MergePolicy
+	if author, ok := s.users[pr.AuthorID]; ok {
+		if t, ok := s.teams[author.TeamName]; ok {
+			policy = models.MergePolicy{
+				MinApprovals:          t.requiredApprovals,
+				NoChangesRequested:    t.noChangesRequested,
+				AuthorNotLastApprover: t.authorNotLastApprover,
+			}
+		}
+	}
+
+	if unmet := policy.Check(pr.AuthorID, pr.reviews()); len(unmet) > 0 {
+		if override == nil {
+			return nil, fmt.Errorf("%s: %w", op, &models.MergePolicyError{Unmet: unmet})
+		}
+
+		// Слияние в обход политики записываем в журнал вместе с причиной
+		conditions := make([]string, len(unmet))
+		for i, c := range unmet {
+			conditions[i] = c.Condition
+		}
+		s.recordEvents(models.AssignmentEvent{
+			Type:          models.EventOverride,
+			PullRequestID: pullRequestID,
+			Actor:         actor,
+			OldValue:      strings.Join(conditions, ","),
+			Comment:       override.Reason,
+		})
+	}
+
+	oldStatus := pr.Status
+	now := time.Now()
+	pr.Status = models.StatusMerged
+	pr.MergedAt = &now
+	s.recordEvents(models.AssignmentEvent{
+		Type:          models.EventMerge,
+		PullRequestID: pullRequestID,
+		Actor:         actor,
+		OldValue:      oldStatus,
+		NewValue:      pr.Status,
+	})
+
+	result := pr.model()
+	return &result, nil
+}
+
+// ChangePullRequestStatus - перевести PR в новый статус по переходу жизненного цикла и назначить
+// ревьюеров reviewers (если переход открывает PR). Недопустимый переход возвращает ошибку,
+// обёрнутую в models.ErrInvalidTransition. Неактивных ревьюеров открываемого PR planner заменяет
+// под той же блокировкой; отчёт о замене возвращается, только если такие ревьюеры были
+func (s *Storage) ChangePullRequestStatus(ctx context.Context, pullRequestID string, transition models.Transition, reviewers []string, planner storage.ReassignmentPlanner, actor string) (*models.PullRequest, *models.ReassignmentReport, error) {
+	const op = "storage.memory.ChangePullRequestStatus"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 1. Проверяем переход
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if err := transition.Check(pr.Status); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, reviewerID := range reviewers {
+		if user, ok := s.users[reviewerID]; !ok || !user.IsActive {
+			return nil, nil, fmt.Errorf("%s: %w", op, storage.ErrReviewerInactive)
+		}
+	}
+
+	// 2. Планируем замену неактивных ревьюеров открываемого PR до изменений,
+	// чтобы ошибка плана ничего не меняла
+	var planned models.PullRequest
+	var inactive []string
+	var moves []models.Reassignment
+	if transition.To == models.StatusOpen {
+		planned = pr.model()
+		planned.Status = transition.To
+		for _, reviewerID := range reviewers {
+			if !slices.Contains(planned.AssignedReviewers, reviewerID) {
+				planned.AssignedReviewers = append(planned.AssignedReviewers, reviewerID)
+			}
+		}
+		for _, reviewerID := range planned.AssignedReviewers {
+			if user, ok := s.users[reviewerID]; ok && !user.IsActive {
+				inactive = append(inactive, reviewerID)
+			}
+		}
+
+		if len(inactive) > 0 {
+			var err error
+			moves, err = planner(ctx, lockedReader{s: s}, []models.PullRequest{planned}, inactive)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+			if err := s.checkReassignments(moves, inactive); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	// 3. Меняем статус; closed_at выставляется при закрытии и сбрасывается при переоткрытии
+	now := time.Now()
+	oldStatus := pr.Status
+	pr.Status = transition.To
+	pr.ClosedAt = nil
+	if transition.To == models.StatusClosed {
+		pr.ClosedAt = &now
+	}
+
+	// 4. Назначаем ревьюеров; уже назначенные пропускаются и в журнал не попадают
+	var assigned []string
+	for _, reviewerID := range reviewers {
+		if pr.reviewerIndex(reviewerID) < 0 {
+			pr.reviewers = append(pr.reviewers, newReviewer(reviewerID, now))
+			assigned = append(assigned, reviewerID)
+		}
+	}
+
+	// 5. Записываем переход и назначения в журнал
+	events := []models.AssignmentEvent{{
+		Type:          transition.Name,
+		PullRequestID: pullRequestID,
+		Actor:         actor,
+		OldValue:      oldStatus,
+		NewValue:      transition.To,
+	}}
+	for _, reviewerID := range assigned {
+		events = append(events, models.AssignmentEvent{
+			Type:          models.EventAssign,
+			PullRequestID: pullRequestID,
+			Actor:         actor,
+			NewValue:      reviewerID,
+		})
+	}
+	s.recordEvents(events...)
+
+	// 6. Заменяем неактивных ревьюеров
+	var report *models.ReassignmentReport
+	if len(inactive) > 0 {
+		applied := s.applyReassignments(moves, actor)
+		report = storage.NewReassignmentReport([]models.PullRequest{planned}, inactive, applied)
+	}
+
+	result := pr.model()
+	return &result, report, nil
+}
+
+// SubmitReview - записать решение ревьювера по PR. Решение COMMENT не меняет состояние ревью,
+// но, как и остальные, попадает в журнал вместе с комментарием
+func (s *Storage) SubmitReview(ctx context.Context, pullRequestID, reviewerID, decision, comment, actor string) error {
+	const op = "storage.memory.SubmitReview"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := models.ReviewStateOf(decision)
+	if !ok {
+		return fmt.Errorf("%s: unknown decision %s", op, decision)
+	}
+
+	// Решение принимается только на открытом PR
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok || pr.Status != models.StatusOpen {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotAssigned)
+	}
+
+	i := pr.reviewerIndex(reviewerID)
+	if i < 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotAssigned)
+	}
+
+	now := time.Now()
+	r := &pr.reviewers[i]
+	oldState := r.state
+	r.state = cmp.Or(state, r.state)
+	r.reviewedAt = &now
+	r.reviewedBy = actor
+
+	s.recordEvents(models.AssignmentEvent{
+		Type:          models.EventReview,
+		PullRequestID: pullRequestID,
+		UserID:        reviewerID,
+		Actor:         actor,
+		OldValue:      oldState,
+		NewValue:      r.state,
+		Comment:       comment,
+	})
+
+	return nil
+}
+
+// GetPullRequestReviews - состояния ревью всех назначенных на PR ревьюеров
+func (s *Storage) GetPullRequestReviews(ctx context.Context, pullRequestID string) ([]models.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return nil, nil
+	}
+
+	return pr.reviews(), nil
+}
+
+// reviews - состояния ревью PR по возрастанию user_id
+func (pr *pullRequest) reviews() []models.Review {
+	var reviews []models.Review
+	for _, r := range pr.reviewers {
+		reviews = append(reviews, models.Review{
+			UserID:     r.userID,
+			State:      r.state,
+			ReviewedAt: r.reviewedAt,
+			ReviewedBy: r.reviewedBy,
+		})
+	}
+	slices.SortFunc(reviews, func(a, b models.Review) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return reviews
+}
+
+// GetPullRequestByID - получить PR по ID
+func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error) {
+	const op = "storage.memory.GetPullRequestByID"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	result := pr.model()
+	return &result, nil
+}
+
+// inRange - попадает ли t в полуоткрытый интервал [from, to); nil-границы не ограничивают
+func inRange(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+// newestFirst - порядок выдачи PR: по (created_at, pull_request_id) по убыванию
+func newestFirst(a, b *pullRequest) int {
+	if c := b.CreatedAt.Compare(*a.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(b.PullRequestID, a.PullRequestID)
+}
+
+// afterCursor - идёт ли PR строго после курсора в порядке newestFirst
+func afterCursor(pr *pullRequest, cursor *models.PageCursor) bool {
+	if cursor == nil {
+		return true
+	}
+	if c := pr.CreatedAt.Compare(cursor.CreatedAt); c != 0 {
+		return c < 0
+	}
+	return pr.PullRequestID < cursor.PullRequestID
+}
+
+// page - первые limit PR и курсор следующей страницы (nil, если страница последняя)
+func page(prs []*pullRequest, limit int) ([]*pullRequest, *models.PageCursor) {
+	slices.SortFunc(prs, newestFirst)
+	if len(prs) <= limit {
+		return prs, nil
+	}
+
+	prs = prs[:limit]
+	last := prs[len(prs)-1]
+	return prs, &models.PageCursor{CreatedAt: *last.CreatedAt, PullRequestID: last.PullRequestID}
+}
+
+// ListPullRequests - страница PR по фильтру, от новых к старым, вместе с ревьюерами.
+// Возвращает курсор следующей страницы или nil, если страница последняя
+func (s *Storage) ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequest, *models.PageCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*pullRequest
+	for _, pr := range s.pullRequests {
+		if filter.Status != "" && pr.Status != filter.Status {
+			continue
+		}
+		if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+			continue
+		}
+		if filter.TeamName != "" {
+			author, ok := s.users[pr.AuthorID]
+			if !ok || author.TeamName != filter.TeamName {
+				continue
+			}
+		}
+		if filter.ReviewerID != "" && pr.reviewerIndex(filter.ReviewerID) < 0 {
+			continue
+		}
+		if !inRange(*pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+			continue
+		}
+		if filter.MergedFrom != nil || filter.MergedTo != nil {
+			if pr.MergedAt == nil || !inRange(*pr.MergedAt, filter.MergedFrom, filter.MergedTo) {
+				continue
+			}
+		}
+		if !afterCursor(pr, filter.After) {
+			continue
+		}
+		matched = append(matched, pr)
+	}
+
+	matched, next := page(matched, filter.Limit)
+
+	var pullRequests []models.PullRequest
+	for _, pr := range matched {
+		result := pr.model()
+		slices.Sort(result.AssignedReviewers)
+		pullRequests = append(pullRequests, result)
+	}
+
+	return pullRequests, next, nil
+}
+
+// ReassignReviewer - заменить ревьювера на другого в PR. Статус PR и активность нового ревьювера
+// проверяются под блокировкой хранилища, поэтому проверки обработчика не могут устареть к моменту записи
+func (s *Storage) ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID, newReviewerID, actor string) error {
+	const op = "storage.memory.ReassignReviewer"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	switch pr.Status {
+	case models.StatusOpen:
+	case models.StatusMerged:
+		return fmt.Errorf("%s: %w", op, storage.ErrPRMerged)
+	default:
+		return fmt.Errorf("%s: %w", op, storage.ErrPRNotOpen)
+	}
+
+	if user, ok := s.users[newReviewerID]; !ok || !user.IsActive {
+		return fmt.Errorf("%s: %w", op, storage.ErrReviewerInactive)
+	}
+	if pr.reviewerIndex(oldReviewerID) < 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotAssigned)
+	}
+	if pr.reviewerIndex(newReviewerID) >= 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAlreadyAssigned)
+	}
+
+	s.replaceReviewer(pr, oldReviewerID, newReviewerID)
+	s.recordEvents(models.AssignmentEvent{
+		Type:          models.EventReassign,
+		PullRequestID: pullRequestID,
+		Actor:         actor,
+		OldValue:      oldReviewerID,
+		NewValue:      newReviewerID,
+	})
+
+	return nil
+}
+
+// IsReviewerAssigned - проверить, назначен ли пользователь ревьювером на PR
+func (s *Storage) IsReviewerAssigned(ctx context.Context, pullRequestID, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pr, ok := s.pullRequests[pullRequestID]
+	return ok && pr.reviewerIndex(userID) >= 0, nil
+}
+
+// GetUserAssignedPullRequests - страница PR, где пользователь назначен ревьювером, от новых к старым.
+// Возвращает курсор следующей страницы или nil, если страница последняя
+func (s *Storage) GetUserAssignedPullRequests(ctx context.Context, filter models.ReviewFilter) ([]models.PullRequestShort, *models.PageCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*pullRequest
+	for _, pr := range s.pullRequests {
+		if pr.reviewerIndex(filter.UserID) < 0 {
+			continue
+		}
+		if filter.Status != "" && pr.Status != filter.Status {
+			continue
+		}
+		if !afterCursor(pr, filter.After) {
+			continue
+		}
+		matched = append(matched, pr)
+	}
+
+	matched, next := page(matched, filter.Limit)
+
+	var pullRequests []models.PullRequestShort
+	for _, pr := range matched {
+		pullRequests = append(pullRequests, models.PullRequestShort{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          pr.Status,
+		})
+	}
+
+	return pullRequests, next, nil
+}
+
+// openReviewsOfUsers - открытые PR, где ревьювером назначен кто-то из пользователей, от старых к новым
+func (s *Storage) openReviewsOfUsers(userIDs []string) []models.PullRequest {
+	var matched []*pullRequest
+	for _, pr := range s.pullRequests {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		if slices.ContainsFunc(pr.reviewers, func(r reviewer) bool { return slices.Contains(userIDs, r.userID) }) {
+			matched = append(matched, pr)
+		}
+	}
+
+	// От старых к новым
+	slices.SortFunc(matched, func(a, b *pullRequest) int {
+		return newestFirst(b, a)
+	})
+
+	var pullRequests []models.PullRequest
+	for _, pr := range matched {
+		result := pr.model()
+		slices.Sort(result.AssignedReviewers)
+		pullRequests = append(pullRequests, result)
+	}
+
+	return pullRequests
+}
+
+// GetPullRequestHistory - журнал событий PR в порядке записи
+func (s *Storage) GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]models.AssignmentEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.AssignmentEvent
+	for _, event := range s.events {
+		if event.PullRequestID == pullRequestID {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// CheckUserExists - проверить, существует ли пользователь
+func (s *Storage) CheckUserExists(ctx context.Context, userID string) error {
+	const op = "storage.memory.CheckUserExists"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// GetReviewerStats - статистика ревьюеров: назначения (всего, на открытых и на слитых PR)
+// считаются по времени назначения, переназначения на других - по времени переназначения.
+// Пустые поля фильтра не ограничивают выборку
+func (s *Storage) GetReviewerStats(ctx context.Context, filter models.StatsFilter) ([]models.ReviewerStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byUser := make(map[string]*models.ReviewerStats)
+	for _, user := range s.users {
+		if filter.TeamName != "" && user.TeamName != filter.TeamName {
+			continue
+		}
+		byUser[user.UserID] = &models.ReviewerStats{UserID: user.UserID, UserName: user.UserName, TeamName: user.TeamName}
+	}
+
+	// Текущие назначения
+	for _, pr := range s.pullRequests {
+		for _, r := range pr.reviewers {
+			st, ok := byUser[r.userID]
+			if !ok || !inRange(r.assignedAt, filter.From, filter.To) {
+				continue
+			}
+			st.TotalAssignments++
+			switch pr.Status {
+			case models.StatusOpen:
+				st.OpenAssignments++
+			case models.StatusMerged:
+				st.MergedReviews++
+			}
+		}
+	}
+
+	// Назначения, переданные другим ревьюерам
+	for _, re := range s.reassignments {
+		st, ok := byUser[re.oldReviewerID]
+		if !ok {
+			continue
+		}
+		if inRange(re.assignedAt, filter.From, filter.To) {
+			st.TotalAssignments++
+		}
+		if inRange(re.reassignedAt, filter.From, filter.To) {
+			st.ReassignedAway++
+		}
+	}
+
+	var stats []models.ReviewerStats
+	for _, st := range byUser {
+		stats = append(stats, *st)
+	}
+	slices.SortFunc(stats, func(a, b models.ReviewerStats) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return stats, nil
+}
+
+// GetPullRequestStats - статистика PR: количество по статусам для PR, созданных за период,
+// число слияний и медиана/p90 времени до слияния для PR, слитых за период.
+// Считается всего, по командам и по авторам
+func (s *Storage) GetPullRequestStats(ctx context.Context, filter models.StatsFilter) (*models.PullRequestStatsReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ключ группы: уровень задаётся флагами (как GROUPING() в postgres), а не пустыми team/author,
+	// иначе авторы без команды смешались бы с итогом
+	type groupKey struct {
+		teamGrouped, authorGrouped bool
+		team, author               string
+	}
+	groups := make(map[groupKey]*models.PullRequestStats)
+	durations := make(map[groupKey][]float64)
+	group := func(key groupKey) *models.PullRequestStats {
+		st, ok := groups[key]
+		if !ok {
+			st = &models.PullRequestStats{ByStatus: make(map[string]int)}
+			groups[key] = st
+		}
+		return st
+	}
+
+	// 1. Проходим по PR, раскладывая каждый в итог, команду и автора
+	for _, pr := range s.pullRequests {
+		author, ok := s.users[pr.AuthorID]
+		if !ok || (filter.TeamName != "" && author.TeamName != filter.TeamName) {
+			continue
+		}
+
+		keys := []groupKey{
+			{teamGrouped: true, authorGrouped: true},
+			{authorGrouped: true, team: author.TeamName},
+			{team: author.TeamName, author: pr.AuthorID},
+		}
+		if inRange(*pr.CreatedAt, filter.From, filter.To) {
+			for _, key := range keys {
+				st := group(key)
+				st.ByStatus[pr.Status]++
+				st.Created++
+			}
+		}
+		if pr.MergedAt != nil && inRange(*pr.MergedAt, filter.From, filter.To) {
+			for _, key := range keys {
+				group(key).Merged++
+				durations[key] = append(durations[key], pr.MergedAt.Sub(*pr.CreatedAt).Seconds())
+			}
+		}
+	}
+
+	// 2. Время до слияния
+	for key, values := range durations {
+		slices.Sort(values)
+		median, p90 := percentile(values, 0.5), percentile(values, 0.9)
+		groups[key].MedianTimeToMergeSeconds = &median
+		groups[key].P90TimeToMergeSeconds = &p90
+	}
+
+	// 3. Раскладываем группы по уровням
+	report := &models.PullRequestStatsReport{
+		Total:   models.PullRequestStats{ByStatus: make(map[string]int)},
+		Teams:   []models.TeamPullRequestStats{},
+		Authors: []models.AuthorPullRequestStats{},
+	}
+	for key, st := range groups {
+		switch {
+		case key.teamGrouped:
+			report.Total = *st
+		case key.authorGrouped:
+			report.Teams = append(report.Teams, models.TeamPullRequestStats{TeamName: key.team, PullRequestStats: *st})
+		default:
+			report.Authors = append(report.Authors, models.AuthorPullRequestStats{AuthorID: key.author, TeamName: key.team, PullRequestStats: *st})
+		}
+	}
+
+	slices.SortFunc(report.Teams, func(a, b models.TeamPullRequestStats) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	slices.SortFunc(report.Authors, func(a, b models.AuthorPullRequestStats) int {
+		return strings.Compare(a.AuthorID, b.AuthorID)
+	})
+
+	return report, nil
+}
+
+// percentile - перцентиль p отсортированных значений с линейной интерполяцией (как percentile_cont)
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(pos-float64(lower))
+}
+
+// GetOpenPullRequestsReport - открытые PR по командам авторов (всего и без активных ревьюеров)
+// и число открытых PR на каждом активном ревьювере. Команды и активные пользователи
+// без открытых PR попадают в отчёт с нулями; неактивные пользователи в отчёт не попадают
+func (s *Storage) GetOpenPullRequestsReport(ctx context.Context) (*models.OpenPullRequestsReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byTeam := make(map[string]*models.TeamOpenPullRequests)
+	for name := range s.teams {
+		byTeam[name] = &models.TeamOpenPullRequests{TeamName: name}
+	}
+	byUser := make(map[string]*models.ReviewerOpenReviews)
+	for _, user := range s.users {
+		if user.IsActive {
+			byUser[user.UserID] = &models.ReviewerOpenReviews{UserID: user.UserID, TeamName: user.TeamName}
+		}
+	}
+
+	for _, pr := range s.pullRequests {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		if author, ok := s.users[pr.AuthorID]; ok {
+			if team, ok := byTeam[author.TeamName]; ok {
+				team.Open++
+				// Деактивация без кандидата на замену оставляет на PR неактивного ревьювера
+				if !slices.ContainsFunc(pr.reviewers, func(r reviewer) bool {
+					user, ok := s.users[r.userID]
+					return ok && user.IsActive
+				}) {
+					team.WithoutReviewers++
+				}
+			}
+		}
+		for _, r := range pr.reviewers {
+			if reviewer, ok := byUser[r.userID]; ok {
+				reviewer.OpenReviews++
+			}
+		}
+	}
+
+	report := &models.OpenPullRequestsReport{}
+	for _, team := range byTeam {
+		report.Teams = append(report.Teams, *team)
+	}
+	slices.SortFunc(report.Teams, func(a, b models.TeamOpenPullRequests) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	for _, reviewer := range byUser {
+		report.Reviewers = append(report.Reviewers, *reviewer)
+	}
+	slices.SortFunc(report.Reviewers, func(a, b models.ReviewerOpenReviews) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return report, nil
+}
+
+// ReserveIdempotencyKey - занять ключ идемпотентности API-ключа apiKeyID на ttl. Возвращает nil, если ключ
+// занят этим вызовом, иначе - запись, сохранённую первым запросом с этим ключом. Истёкшие ключи удаляются.
+// Ключ без сохранённого ответа, занятый раньше чем lease назад, считается брошенным (процесс упал
+// посреди запроса) и занимается заново
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, apiKeyID, key, requestHash string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, reserved := range s.idempotency {
+		if !reserved.expiresAt.After(now) {
+			delete(s.idempotency, k)
+		}
+	}
+
+	scope := idempotencyScope{apiKeyID: apiKeyID, key: key}
+	// Незавершённый ключ старше lease брошен - занимаем его заново
+	if reserved, ok := s.idempotency[scope]; ok && (reserved.record.Completed || reserved.reservedAt.After(now.Add(-lease))) {
+		record := reserved.record
+		record.ResponseBody = slices.Clone(record.ResponseBody)
+		return &record, nil
+	}
+
+	s.idempotency[scope] = &idempotencyKey{
+		record:     models.IdempotencyRecord{APIKeyID: apiKeyID, Key: key, RequestHash: requestHash},
+		reservedAt: now,
+		expiresAt:  now.Add(ttl),
+	}
+
+	return nil, nil
+}
+
+// CompleteIdempotencyKey - сохранить ответ на запрос с ключом идемпотентности
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, apiKeyID, key string, status int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reserved, ok := s.idempotency[idempotencyScope{apiKeyID: apiKeyID, key: key}]; ok {
+		reserved.record.Completed = true
+		reserved.record.ResponseStatus = status
+		reserved.record.ResponseBody = slices.Clone(body)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey - освободить ключ, если ответ по нему так и не был сохранён
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, apiKeyID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scope := idempotencyScope{apiKeyID: apiKeyID, key: key}
+	if reserved, ok := s.idempotency[scope]; ok && !reserved.record.Completed {
+		delete(s.idempotency, scope)
+	}
+
+	return nil
+}
+
+// CreateAPIKey - сохранить выданный API-ключ по его хэшу. Возвращает ключ с временем создания
+func (s *Storage) CreateAPIKey(ctx context.Context, key models.APIKey, keyHash string) (*models.APIKey, error) {
+	const op = "storage.memory.CreateAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.apiKeys[key.KeyID]; ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyExists)
+	}
+	for _, existing := range s.apiKeys {
+		if existing.hash == keyHash {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyExists)
+		}
+	}
+
+	key.CreatedAt = time.Now()
+	key.RevokedAt = nil
+	key.RevokedBy = ""
+	s.apiKeys[key.KeyID] = &apiKey{APIKey: key, hash: keyHash}
+
+	return &key, nil
+}
+
+// GetAPIKeyByHash - API-ключ по хэшу, в том числе отозванный
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	const op = "storage.memory.GetAPIKeyByHash"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.apiKeys {
+		if key.hash == keyHash {
+			found := key.APIKey
+			return &found, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+}
+
+// RevokeAPIKey - отозвать API-ключ. Повторный отзыв не меняет время и автора первого
+func (s *Storage) RevokeAPIKey(ctx context.Context, keyID, actor string) (*models.APIKey, error) {
+	const op = "storage.memory.RevokeAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.apiKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		key.RevokedBy = actor
+	}
+
+	revoked := key.APIKey
+	return &revoked, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"main.go/internal/models"
+)
+
+func TestChangePullRequestStatusLogsOnlyNewAssignments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	_, err := store.SaveTeamWithUpdate(ctx, models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", UserName: "Alice", IsActive: true},
+			{UserID: "u2", UserName: "Bob", IsActive: true},
+			{UserID: "u3", UserName: "Carol", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	err = store.CreatePullRequest(ctx, models.PullRequest{
+		PullRequestID:     "pr-1",
+		PullRequestName:   "Add search",
+		AuthorID:          "u1",
+		Status:            models.StatusDraft,
+		AssignedReviewers: []string{"u2"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("failed to create pull request: %v", err)
+	}
+
+	// u2 уже назначен на черновик - назначение пропускается и в журнал не попадает
+	if _, _, err := store.ChangePullRequestStatus(ctx, "pr-1", models.TransitionReady, []string{"u2", "u3"}, nil, "test"); err != nil {
+		t.Fatalf("failed to change status: %v", err)
+	}
+
+	history, err := store.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+
+	var assigned []string
+	for _, event := range history {
+		if event.Type == models.EventAssign {
+			assigned = append(assigned, event.NewValue)
+		}
+	}
+	// Первое событие assign - назначение u2 при создании PR
+	if len(assigned) != 2 || assigned[0] != "u2" || assigned[1] != "u3" {
+		t.Errorf("assign events = %v, want [u2 u3]", assigned)
+	}
+}
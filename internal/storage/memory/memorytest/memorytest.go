@@ -0,0 +1,71 @@
+package memorytest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"main.go/internal/assignment"
+	"main.go/internal/models"
+	"main.go/internal/storage/memory"
+)
+
+// TeamName - имя команды по умолчанию
+const TeamName = "backend"
+
+var members = []models.TeamMember{
+	{UserID: "u1", UserName: "Alice", IsActive: true},
+	{UserID: "u2", UserName: "Bob", IsActive: true},
+	{UserID: "u3", UserName: "Carol", IsActive: true},
+	{UserID: "u4", UserName: "Dave", IsActive: true},
+	{UserID: "u5", UserName: "Eve", IsActive: true},
+}
+
+// Members - первые n активных участников: u1 Alice, u2 Bob, u3 Carol, u4 Dave, u5 Eve
+func Members(n int) []models.TeamMember {
+	return append([]models.TeamMember(nil), members[:n]...)
+}
+
+// New - хранилище в памяти с командой team (без имени - TeamName) и PR prs.
+// PR без статуса создаются открытыми
+func New(t testing.TB, team models.Team, prs ...models.PullRequest) *memory.Storage {
+	t.Helper()
+
+	ctx := context.Background()
+	store := memory.New()
+
+	if team.TeamName == "" {
+		team.TeamName = TeamName
+	}
+	if _, err := store.SaveTeamWithUpdate(ctx, team); err != nil {
+		t.Fatalf("failed to save team: %v", err)
+	}
+
+	for _, pr := range prs {
+		if pr.Status == "" {
+			pr.Status = models.StatusOpen
+		}
+		if err := store.CreatePullRequest(ctx, pr, "test"); err != nil {
+			t.Fatalf("failed to create pull request %s: %v", pr.PullRequestID, err)
+		}
+	}
+
+	return store
+}
+
+// Picker - подбор ревьюеров поверх store со стратегией least_loaded по умолчанию
+func Picker(t testing.TB, store *memory.Storage) *assignment.Picker {
+	t.Helper()
+
+	strategies, err := assignment.NewRegistry(assignment.StrategyLeastLoaded, store)
+	if err != nil {
+		t.Fatalf("failed to init strategies: %v", err)
+	}
+	return assignment.NewPicker(store, strategies)
+}
+
+// Logger - логгер, который ничего не пишет
+func Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
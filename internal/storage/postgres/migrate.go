@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"main.go/internal/storage"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey - ключ advisory lock, под которым применяются миграции:
+// реплики, стартующие одновременно, выполняют их по очереди
+const migrationLockKey = 4_826_331_907
+
+// Migration - версия схемы БД: пара файлов NNNN_name.up.sql / NNNN_name.down.sql
+type Migration struct {
+	Version int
+	Name    string
+	// AppliedAt - когда миграция применена (nil - ещё не применена)
+	AppliedAt *time.Time
+
+	up   string
+	down string
+}
+
+// loadMigrations - миграции из встроенных файлов по возрастанию версии
+func loadMigrations() ([]Migration, error) {
+	return readMigrations(migrationFiles)
+}
+
+// readMigrations - миграции из каталога migrations в fsys по возрастанию версии
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(fileName, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("unexpected migration file %s", fileName)
+		}
+
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %s must be named NNNN_name.%s.sql", fileName, direction)
+		}
+
+		body, err := fs.ReadFile(fsys, path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+
+	return migrations, nil
+}
+
+// withMigrationLock - выполнить fn на отдельном соединении под advisory lock миграций
+func (s *Storage) withMigrationLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	// Блокировка сессионная: снимаем её явно, соединение вернётся в пул
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// appliedMigrations - время применения миграций по версиям
+func appliedMigrations(ctx context.Context, db queryer) (map[int]time.Time, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// MigrationStatus - все известные миграции с отметкой, применены ли они
+func (s *Storage) MigrationStatus(ctx context.Context) ([]Migration, error) {
+	const op = "storage.postgres.MigrationStatus"
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := range migrations {
+			if appliedAt, ok := applied[migrations[i].Version]; ok {
+				migrations[i].AppliedAt = &appliedAt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return migrations, nil
+}
+
+// CheckMigrations - проверить, что все встроенные миграции применены.
+// Блокировку миграций не берёт, поэтому не ждёт, пока их применяет другая реплика
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	const op = "storage.postgres.CheckMigrations"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	applied, err := appliedMigrations(ctx, s.db)
+	if err != nil {
+		// Таблицы schema_migrations ещё нет - не применена ни одна миграция
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || pqErr.Code != undefinedTable {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	pending := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%s: %d %w", op, pending, storage.ErrMigrationsPending)
+	}
+
+	return nil
+}
+
+// MigrateUp - применить все неприменённые миграции по возрастанию версии.
+// Каждая миграция выполняется в своей транзакции вместе с записью в schema_migrations.
+// Возвращает применённые миграции
+func (s *Storage) MigrateUp(ctx context.Context) ([]Migration, error) {
+	const op = "storage.postgres.MigrateUp"
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var done []Migration
+	err = s.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			err := runMigration(ctx, conn, m.up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// MigrateDown - откатить steps последних применённых миграций по убыванию версии.
+// Возвращает откаченные миграции
+func (s *Storage) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	const op = "storage.postgres.MigrateDown"
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var done []Migration
+	err = s.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range slices.Backward(migrations) {
+			if len(done) == steps {
+				break
+			}
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			err := runMigration(ctx, conn, m.down, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// runMigration - выполнить SQL миграции и обновить schema_migrations в одной транзакции
+func runMigration(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Без параметров lib/pq выполняет весь файл одним запросом, в том числе несколько команд
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("no migrations embedded")
+	}
+
+	// Версии идут подряд с 1, у каждой есть up и down
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+		if strings.TrimSpace(m.up) == "" || strings.TrimSpace(m.down) == "" {
+			t.Errorf("migration %04d_%s has an empty up or down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	file := func(body string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(body)}
+	}
+
+	migrations, err := readMigrations(fstest.MapFS{
+		"migrations/0010_second.up.sql":   file("up 10"),
+		"migrations/0010_second.down.sql": file("down 10"),
+		"migrations/0002_first.down.sql":  file("down 2"),
+		"migrations/0002_first.up.sql":    file("up 2"),
+	})
+	if err != nil {
+		t.Fatalf("failed to read migrations: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("migrations = %+v, want 2", migrations)
+	}
+	// Порядок по номеру версии, а не по имени файла
+	if first := migrations[0]; first.Version != 2 || first.Name != "first" || first.up != "up 2" || first.down != "down 2" {
+		t.Errorf("first migration = %+v, want 0002_first", first)
+	}
+	if second := migrations[1]; second.Version != 10 || second.Name != "second" {
+		t.Errorf("second migration = %+v, want 0010_second", second)
+	}
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{
+			"migrations/0001_init.up.sql": file("up"),
+		}},
+		{"unknown direction", fstest.MapFS{
+			"migrations/0001_init.sideways.sql": file("up"),
+		}},
+		{"no version", fstest.MapFS{
+			"migrations/init.up.sql":   file("up"),
+			"migrations/init.down.sql": file("down"),
+		}},
+		{"different names", fstest.MapFS{
+			"migrations/0001_init.up.sql":    file("up"),
+			"migrations/0001_other.down.sql": file("down"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readMigrations(tt.files); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS teams (
+    team_name VARCHAR(255) NOT NULL PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    user_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    user_name VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) REFERENCES teams(team_name),
+    is_active BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) REFERENCES users(user_id),
+    status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN','MERGED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    merged_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests_reviewers (
+    pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id),
+    user_id VARCHAR(255) REFERENCES users(user_id),
+    PRIMARY KEY (pull_request_id, user_id)
+);
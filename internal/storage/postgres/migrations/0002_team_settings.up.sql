@@ -0,0 +1,9 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS assignment_strategy VARCHAR(32);
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS min_reviewers INT NOT NULL DEFAULT 1;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS max_reviewers INT NOT NULL DEFAULT 2;
+
+DO $$ BEGIN
+    ALTER TABLE teams ADD CONSTRAINT teams_reviewers_check
+        CHECK (min_reviewers >= 1 AND max_reviewers >= min_reviewers);
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
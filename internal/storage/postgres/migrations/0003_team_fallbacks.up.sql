@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name VARCHAR(255) REFERENCES teams(team_name),
+    fallback_team_name VARCHAR(255) REFERENCES teams(team_name),
+    position INT NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name != fallback_team_name)
+);
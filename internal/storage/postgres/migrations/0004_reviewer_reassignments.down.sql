@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reviewer_reassignments;
+ALTER TABLE pull_requests_reviewers DROP COLUMN IF EXISTS assigned_at;
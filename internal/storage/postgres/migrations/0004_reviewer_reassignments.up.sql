@@ -0,0 +1,21 @@
+ALTER TABLE pull_requests_reviewers ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ;
+
+UPDATE pull_requests_reviewers prr SET assigned_at = pr.created_at
+    FROM pull_requests pr
+    WHERE pr.pull_request_id = prr.pull_request_id AND prr.assigned_at IS NULL;
+
+ALTER TABLE pull_requests_reviewers
+    ALTER COLUMN assigned_at SET DEFAULT now(),
+    ALTER COLUMN assigned_at SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS reviewer_reassignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id),
+    old_reviewer_id VARCHAR(255) REFERENCES users(user_id),
+    new_reviewer_id VARCHAR(255) REFERENCES users(user_id),
+    assigned_at TIMESTAMPTZ NOT NULL,
+    reassigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reviewer_reassignments_old_reviewer_id_idx
+    ON reviewer_reassignments(old_reviewer_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS assignment_events;
+DROP FUNCTION IF EXISTS assignment_events_append_only();
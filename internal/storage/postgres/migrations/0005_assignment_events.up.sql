@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(32) NOT NULL,
+    pull_request_id VARCHAR(255),
+    user_id VARCHAR(255),
+    actor VARCHAR(255) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS assignment_events_pull_request_id_idx
+    ON assignment_events(pull_request_id, id);
+
+-- Журнал только дописывается
+CREATE OR REPLACE FUNCTION assignment_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER assignment_events_append_only
+    BEFORE UPDATE OR DELETE ON assignment_events
+    FOR EACH ROW EXECUTE FUNCTION assignment_events_append_only();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS pull_requests_author_id_idx;
+DROP INDEX IF EXISTS pull_requests_created_at_idx;
+DROP INDEX IF EXISTS pull_requests_reviewers_user_id_idx;
+DROP INDEX IF EXISTS users_team_name_idx;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users(team_name);
+CREATE INDEX IF NOT EXISTS pull_requests_reviewers_user_id_idx ON pull_requests_reviewers(user_id);
+CREATE INDEX IF NOT EXISTS pull_requests_created_at_idx ON pull_requests(created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS pull_requests_author_id_idx ON pull_requests(author_id);
@@ -0,0 +1,6 @@
+-- Откат невозможен, пока есть PR в статусах DRAFT или CLOSED
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
+
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS pull_requests_status_check,
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN','MERGED'));
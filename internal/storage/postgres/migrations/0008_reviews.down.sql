@@ -0,0 +1,7 @@
+ALTER TABLE assignment_events DROP COLUMN IF EXISTS comment;
+
+ALTER TABLE pull_requests_reviewers
+    DROP CONSTRAINT IF EXISTS pull_requests_reviewers_review_state_check,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS review_state;
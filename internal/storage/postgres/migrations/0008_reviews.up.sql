@@ -0,0 +1,12 @@
+ALTER TABLE pull_requests_reviewers
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(255);
+
+DO $$ BEGIN
+    ALTER TABLE pull_requests_reviewers ADD CONSTRAINT pull_requests_reviewers_review_state_check
+        CHECK (review_state IN ('PENDING','APPROVED','CHANGES_REQUESTED'));
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE assignment_events ADD COLUMN IF NOT EXISTS comment TEXT;
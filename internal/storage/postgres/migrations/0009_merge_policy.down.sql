@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_required_approvals_check,
+    DROP COLUMN IF EXISTS author_not_last_approver,
+    DROP COLUMN IF EXISTS no_changes_requested,
+    DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,10 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS no_changes_requested BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS author_not_last_approver BOOLEAN NOT NULL DEFAULT false;
+
+DO $$ BEGIN
+    ALTER TABLE teams ADD CONSTRAINT teams_required_approvals_check
+        CHECK (required_approvals >= 0 AND required_approvals <= max_reviewers);
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
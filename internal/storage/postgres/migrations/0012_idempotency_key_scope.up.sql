@@ -0,0 +1,7 @@
+-- Ключи идемпотентности действуют в пределах API-ключа: одинаковый Idempotency-Key
+-- у разных клиентов не должен отдавать чужой сохранённый ответ
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys ADD COLUMN api_key_id VARCHAR(32) NOT NULL;
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (api_key_id, idempotency_key);
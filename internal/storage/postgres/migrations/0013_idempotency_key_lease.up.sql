@@ -0,0 +1,3 @@
+-- Время занятия ключа: незавершённую запись старше срока аренды может занять повторный запрос,
+-- иначе ключ запроса, прерванного падением процесса, оставался бы занятым до истечения TTL
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS reserved_at TIMESTAMPTZ NOT NULL DEFAULT now();
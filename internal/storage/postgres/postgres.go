@@ -1,20 +1,38 @@
 package postgres
 
 import (
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	_ "github.com/lib/pq" //
+	"github.com/lib/pq"
 	"main.go/internal/models"
+	"main.go/internal/storage"
+)
+
+// Коды ошибок PostgreSQL
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	checkViolation      = "23514"
+	undefinedTable      = "42P01"
 )
 
 type Storage struct {
 	db *sql.DB
+	// queryTimeout - предельное время одного вызова хранилища (0 - без ограничения)
+	queryTimeout time.Duration
 }
 
-// New - инициализация БД, создание таблиц, если их нет
-func New(storagePath string) (*Storage, error) {
+// New - подключение к БД. Схема создаётся миграциями (MigrateUp)
+func New(storagePath string, queryTimeout time.Duration) (*Storage, error) {
 	const op = "storage.postgres.New"
 
 	db, err := sql.Open("postgres", storagePath)
@@ -22,62 +40,129 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS teams (
-            team_name VARCHAR(255) NOT NULL PRIMARY KEY
-        );`,
-		`CREATE TABLE IF NOT EXISTS users (
-            user_id VARCHAR(255) NOT NULL PRIMARY KEY,
-            user_name VARCHAR(255) NOT NULL,
-            team_name VARCHAR(255) REFERENCES teams(team_name),
-            is_active BOOLEAN NOT NULL DEFAULT true
-        );`,
-		`CREATE TABLE IF NOT EXISTS pull_requests (
-            pull_request_id VARCHAR(255) NOT NULL PRIMARY KEY,
-            pull_request_name VARCHAR(255) NOT NULL,
-            author_id VARCHAR(255) REFERENCES users(user_id),
-            status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN','MERGED')),
-            created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-            merged_at TIMESTAMPTZ
-        );`,
-		`CREATE TABLE IF NOT EXISTS pull_requests_reviewers (
-            pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id),
-            user_id VARCHAR(255) REFERENCES users(user_id),
-            PRIMARY KEY (pull_request_id, user_id)
-        );`,
-	}
-
-	for _, q := range queries {
-		if _, err := db.Exec(q); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
+	return &Storage{db: db, queryTimeout: queryTimeout}, nil
+}
+
+// withTimeout - ограничить контекст вызова хранилища queryTimeout
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.queryTimeout)
+}
+
+// Ping - проверить соединение с БД
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.postgres.Ping"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Close - закрыть пул соединений с БД: новые запросы получают ошибку,
+// выполняющиеся дожидаются завершения
+func (s *Storage) Close() error {
+	const op = "storage.postgres.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// execer - общий интерфейс *sql.DB и *sql.Tx для запросов без результата
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// queryer - общий интерфейс *sql.DB и *sql.Tx для запросов со строками результата
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// recordEvents - дописать события в журнал назначений одним запросом
+func recordEvents(ctx context.Context, db execer, events []models.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	types := make([]string, len(events))
+	prIDs := make([]string, len(events))
+	userIDs := make([]string, len(events))
+	actors := make([]string, len(events))
+	oldValues := make([]string, len(events))
+	newValues := make([]string, len(events))
+	comments := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+		prIDs[i] = event.PullRequestID
+		userIDs[i] = event.UserID
+		actors[i] = event.Actor
+		oldValues[i] = event.OldValue
+		newValues[i] = event.NewValue
+		comments[i] = event.Comment
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO assignment_events (event_type, pull_request_id, user_id, actor, old_value, new_value, comment)
+		SELECT t, NULLIF(pr, ''), NULLIF(u, ''), a, NULLIF(o, ''), NULLIF(n, ''), NULLIF(c, '')
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[]) AS e(t, pr, u, a, o, n, c)
+	`, pq.Array(types), pq.Array(prIDs), pq.Array(userIDs), pq.Array(actors), pq.Array(oldValues), pq.Array(newValues), pq.Array(comments))
+	if err != nil {
+		return fmt.Errorf("failed to record events: %w", err)
 	}
 
-	return &Storage{db: db}, nil
+	return nil
+}
+
+// activityEvent - событие изменения флага активности пользователя
+func activityEvent(userID, actor string, wasActive, isActive bool) models.AssignmentEvent {
+	eventType := models.EventDeactivate
+	if isActive {
+		eventType = models.EventActivate
+	}
+
+	return models.AssignmentEvent{
+		Type:     eventType,
+		UserID:   userID,
+		Actor:    actor,
+		OldValue: strconv.FormatBool(wasActive),
+		NewValue: strconv.FormatBool(isActive),
+	}
 }
 
 // SaveTeam - сохранение команды с участниками, принимает структуру team
-func (s *Storage) SaveTeam(team models.Team) error {
+func (s *Storage) SaveTeam(ctx context.Context, team models.Team) error {
 
 	const op = "storage.postgres.SaveTeam"
-	stmt, err := s.db.Prepare("INSERT INTO teams(team_name) VALUES($1)")
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	stmt, err := s.db.PrepareContext(ctx, "INSERT INTO teams(team_name) VALUES($1)")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec(team.TeamName)
+	_, err = stmt.ExecContext(ctx, team.TeamName)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	memberStmt, err := s.db.Prepare("INSERT INTO users(user_id, user_name, team_name, is_active) VALUES($1,$2,$3,$4)")
+	memberStmt, err := s.db.PrepareContext(ctx, "INSERT INTO users(user_id, user_name, team_name, is_active) VALUES($1,$2,$3,$4)")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	defer memberStmt.Close()
 
 	for i := range team.Members {
-		_, err = memberStmt.Exec(
+		_, err = memberStmt.ExecContext(ctx,
 			team.Members[i].UserID,
 			team.Members[i].UserName,
 			team.TeamName,
@@ -92,10 +177,12 @@ func (s *Storage) SaveTeam(team models.Team) error {
 }
 
 // GetTeamMembers - получить членов команды
-func (s *Storage) GetTeamMembers(teamName string) ([]models.TeamMember, error) {
+func (s *Storage) GetTeamMembers(ctx context.Context, teamName string) ([]models.TeamMember, error) {
 	const op = "storage.postgres.GetTeamMembers"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	rows, err := s.db.Query(`
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT user_id, user_name, is_active
 		FROM users
 		WHERE team_name = $1
@@ -120,11 +207,13 @@ func (s *Storage) GetTeamMembers(teamName string) ([]models.TeamMember, error) {
 }
 
 // TeamExists - проверить, существует ли команда
-func (s *Storage) TeamExists(teamName string) (bool, error) {
+func (s *Storage) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	const op = "storage.postgres.TeamExists"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
 	var exists bool
-	err := s.db.QueryRow(`
+	err := s.db.QueryRowContext(ctx, `
 		SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)
 	`, teamName).Scan(&exists)
 
@@ -135,11 +224,124 @@ func (s *Storage) TeamExists(teamName string) (bool, error) {
 	return exists, nil
 }
 
+// GetTeam - получить команду с настройками и участниками
+func (s *Storage) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	const op = "storage.postgres.GetTeam"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var team models.Team
+	var strategy sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT team_name, assignment_strategy, min_reviewers, max_reviewers,
+			required_approvals, no_changes_requested, author_not_last_approver
+		FROM teams
+		WHERE team_name = $1
+	`, teamName).Scan(&team.TeamName, &strategy, &team.MinReviewers, &team.MaxReviewers,
+		&team.RequiredApprovals, &team.NoChangesRequested, &team.AuthorNotLastApprover)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTeamNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	team.AssignmentStrategy = strategy.String
+
+	members, err := s.GetTeamMembers(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	team.Members = members
+
+	fallbacks, err := getFallbackTeams(ctx, s.db, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	team.FallbackTeams = fallbacks
+
+	return &team, nil
+}
+
+// GetTeamByUser - получить команду пользователя с её настройками (без списка участников)
+func (s *Storage) GetTeamByUser(ctx context.Context, userID string) (*models.Team, error) {
+	const op = "storage.postgres.GetTeamByUser"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	team, err := teamByUser(ctx, s.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return team, nil
+}
+
+// teamByUser - команда пользователя с настройками и резервными командами
+func teamByUser(ctx context.Context, db queryer, userID string) (*models.Team, error) {
+	var team models.Team
+	var strategy sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT t.team_name, t.assignment_strategy, t.min_reviewers, t.max_reviewers,
+			t.required_approvals, t.no_changes_requested, t.author_not_last_approver
+		FROM users u
+		INNER JOIN teams t ON t.team_name = u.team_name
+		WHERE u.user_id = $1
+	`, userID).Scan(&team.TeamName, &strategy, &team.MinReviewers, &team.MaxReviewers,
+		&team.RequiredApprovals, &team.NoChangesRequested, &team.AuthorNotLastApprover)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	team.AssignmentStrategy = strategy.String
+
+	fallbacks, err := getFallbackTeams(ctx, db, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	team.FallbackTeams = fallbacks
+
+	return &team, nil
+}
+
+// getFallbackTeams - резервные команды в порядке приоритета
+func getFallbackTeams(ctx context.Context, db queryer, teamName string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT fallback_team_name
+		FROM team_fallbacks
+		WHERE team_name = $1
+		ORDER BY position
+	`, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fallbacks []string
+	for rows.Next() {
+		var fallback string
+		if err := rows.Scan(&fallback); err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, fallback)
+	}
+
+	return fallbacks, rows.Err()
+}
+
 // SaveTeamWithUpdate - создать команду или обновить членов
-func (s *Storage) SaveTeamWithUpdate(team models.Team) (bool, error) {
+func (s *Storage) SaveTeamWithUpdate(ctx context.Context, team models.Team) (bool, error) {
 	const op = "storage.postgres.SaveTeamWithUpdate"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
 	}
@@ -147,21 +349,97 @@ func (s *Storage) SaveTeamWithUpdate(team models.Team) (bool, error) {
 
 	// Проверяем, существует ли команда
 	var exists bool
-	err = tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`, team.TeamName).Scan(&exists)
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`, team.TeamName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	// Если команда не существует, создаём её
 	if !exists {
-		_, err = tx.Exec(`INSERT INTO teams (team_name) VALUES ($1)`, team.TeamName)
+		_, err = tx.ExecContext(ctx, `INSERT INTO teams (team_name) VALUES ($1)`, team.TeamName)
 		if err != nil {
 			return false, fmt.Errorf("%s: failed to create team: %w", op, err)
 		}
 	}
 
+	// Обновляем переданные настройки команды (пустые значения оставляют текущие)
+	hasNewSettings := false
+	if team.AssignmentStrategy != "" || team.MinReviewers > 0 || team.MaxReviewers > 0 ||
+		team.RequiredApprovals != nil || team.NoChangesRequested != nil || team.AuthorNotLastApprover != nil {
+		res, err := tx.ExecContext(ctx, `
+			WITH settings AS (
+				SELECT
+					COALESCE(NULLIF($2, ''), assignment_strategy) AS assignment_strategy,
+					COALESCE(NULLIF($3, 0), min_reviewers) AS min_reviewers,
+					COALESCE(NULLIF($4, 0), max_reviewers) AS max_reviewers,
+					COALESCE($5::int, required_approvals) AS required_approvals,
+					COALESCE($6::boolean, no_changes_requested) AS no_changes_requested,
+					COALESCE($7::boolean, author_not_last_approver) AS author_not_last_approver
+				FROM teams
+				WHERE team_name = $1
+			)
+			UPDATE teams t SET
+				assignment_strategy = s.assignment_strategy,
+				min_reviewers = s.min_reviewers,
+				max_reviewers = s.max_reviewers,
+				required_approvals = s.required_approvals,
+				no_changes_requested = s.no_changes_requested,
+				author_not_last_approver = s.author_not_last_approver
+			FROM settings s
+			WHERE t.team_name = $1
+			AND (t.assignment_strategy, t.min_reviewers, t.max_reviewers,
+				t.required_approvals, t.no_changes_requested, t.author_not_last_approver)
+				IS DISTINCT FROM (s.assignment_strategy, s.min_reviewers, s.max_reviewers,
+				s.required_approvals, s.no_changes_requested, s.author_not_last_approver)
+		`, team.TeamName, team.AssignmentStrategy, team.MinReviewers, team.MaxReviewers,
+			team.RequiredApprovals, team.NoChangesRequested, team.AuthorNotLastApprover)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == checkViolation {
+			return false, fmt.Errorf("%s: %w: %s", op, storage.ErrInvalidSettings, pqErr.Constraint)
+		}
+		if err != nil {
+			return false, fmt.Errorf("%s: failed to update team settings: %w", op, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		hasNewSettings = affected > 0
+	}
+
+	// Если передан список резервных команд (в том числе пустой) - заменяем текущий
+	if team.FallbackTeams != nil {
+		var current []string
+		err = tx.QueryRowContext(ctx, `
+			SELECT COALESCE(array_agg(fallback_team_name ORDER BY position), '{}')
+			FROM team_fallbacks
+			WHERE team_name = $1
+		`, team.TeamName).Scan(pq.Array(&current))
+		if err != nil {
+			return false, fmt.Errorf("%s: failed to get fallback teams: %w", op, err)
+		}
+
+		if !slices.Equal(current, team.FallbackTeams) {
+			_, err = tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, team.TeamName)
+			if err != nil {
+				return false, fmt.Errorf("%s: failed to clear fallback teams: %w", op, err)
+			}
+
+			for i, fallback := range team.FallbackTeams {
+				_, err = tx.ExecContext(ctx, `
+					INSERT INTO team_fallbacks (team_name, fallback_team_name, position)
+					VALUES ($1, $2, $3)
+				`, team.TeamName, fallback, i)
+				if err != nil {
+					return false, fmt.Errorf("%s: failed to add fallback team: %w", op, err)
+				}
+			}
+			hasNewSettings = true
+		}
+	}
+
 	// Получаем текущих членов команды
-	rows, err := tx.Query(`
+	rows, err := tx.QueryContext(ctx, `
 		SELECT user_id FROM users WHERE team_name = $1
 	`, team.TeamName)
 	if err != nil {
@@ -187,15 +465,15 @@ func (s *Storage) SaveTeamWithUpdate(team models.Team) (bool, error) {
 		}
 	}
 
-	// Если нет новых членов и команда уже существует → ошибка
-	if exists && !hasNewMembers {
-		return false, fmt.Errorf("%s: team already exists with same members", op)
+	// Если нет новых членов, настройки не изменились и команда уже существует → ошибка
+	if exists && !hasNewMembers && !hasNewSettings {
+		return false, fmt.Errorf("%s: %w", op, storage.ErrTeamExists)
 	}
 
 	// Добавляем только новых членов
 	for _, member := range team.Members {
 		if !existingMembers[member.UserID] {
-			_, err = tx.Exec(`
+			_, err = tx.ExecContext(ctx, `
 				INSERT INTO users (user_id, user_name, team_name, is_active)
 				VALUES ($1, $2, $3, $4)
 				ON CONFLICT (user_id) DO UPDATE SET
@@ -218,28 +496,40 @@ func (s *Storage) SaveTeamWithUpdate(team models.Team) (bool, error) {
 }
 
 // SetUserActive - меняет флаг активности пользователя по id
-func (s *Storage) SetUserActive(userID string, isActive bool) (*models.User, error) {
+func (s *Storage) SetUserActive(ctx context.Context, userID string, isActive bool, actor string) (*models.User, error) {
 	const op = "storage.postgres.SetUserActive"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
 	log.Printf("%s: updating user %s to is_active=%v", op, userID, isActive)
 
-	var user models.User
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
 
-	err := s.db.QueryRow(`
-        UPDATE users 
-        SET is_active = $1 
-        WHERE user_id = $2
-        RETURNING user_id, user_name, team_name, is_active
+	var user models.User
+	var wasActive bool
+
+	// Во FROM - строка до обновления, из неё берём прежнее значение флага
+	err = tx.QueryRowContext(ctx, `
+        UPDATE users u
+        SET is_active = $1
+        FROM users prev
+        WHERE u.user_id = $2 AND prev.user_id = u.user_id
+        RETURNING u.user_id, u.user_name, u.team_name, u.is_active, prev.is_active
     `, isActive, userID).Scan(
 		&user.UserID,
 		&user.UserName,
 		&user.TeamName,
 		&user.IsActive,
+		&wasActive,
 	)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, sql.ErrNoRows) {
 		log.Printf("%s: user %s not found", op, userID)
-		return nil, fmt.Errorf("%s: user not found", op)
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 	}
 
 	if err != nil {
@@ -247,235 +537,1167 @@ func (s *Storage) SetUserActive(userID string, isActive bool) (*models.User, err
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if wasActive != isActive {
+		if err := recordEvents(ctx, tx, []models.AssignmentEvent{activityEvent(userID, actor, wasActive, isActive)}); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
 	log.Printf("%s: user updated successfully: %+v", op, user)
 
 	return &user, nil
 }
 
-// CheckAuthorExist - функция проверяет, существует ли автор
-func (s *Storage) CheckAuthorExist(authorID string) error {
-	const op = "storage.postgres.CheckAuthorExist"
-	var userID string
-	err := s.db.QueryRow(`SELECT user_id FROM users WHERE user_id = $1`, authorID).Scan(&userID)
+// DeactivateUser - в одной транзакции деактивирует пользователя и переназначает его открытые ревью.
+// Замены подбирает planner по данным этой же транзакции, под блокировкой пользователя и его PR
+func (s *Storage) DeactivateUser(ctx context.Context, userID string, planner storage.ReassignmentPlanner, actor string) (*models.User, *models.ReassignmentReport, error) {
+	const op = "storage.postgres.DeactivateUser"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// 1. Блокируем пользователя: назначение его ревьювером в других транзакциях
+	// (проверка внешнего ключа) ждёт, пока эта не завершится
+	var user models.User
+	err = tx.QueryRowContext(ctx, `
+		SELECT user_id, user_name, team_name, is_active
+		FROM users
+		WHERE user_id = $1
+		FOR UPDATE
+	`, userID).Scan(&user.UserID, &user.UserName, &user.TeamName, &user.IsActive)
 
-	if err == sql.ErrNoRows {
-		return fmt.Errorf("%s: author are not found", op)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 	}
 
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nil
+	// 2. Деактивируем
+	if user.IsActive {
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET is_active = false WHERE user_id = $1`, userID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := recordEvents(ctx, tx, []models.AssignmentEvent{activityEvent(userID, actor, true, false)}); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		user.IsActive = false
+	}
+
+	// 3. Переназначаем открытые ревью
+	report, err := reassignOpenReviews(ctx, tx, []string{userID}, planner, actor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return &user, report, nil
 }
 
-// CreatePullRequest - функция создает pull request и назначает ревьюеров
-func (s *Storage) CreatePullRequest(pr models.PullRequest) error {
-	const op = "storage.postgres.CreatePullRequest"
+// txReader - storage.PlanReader поверх транзакции: план переназначений видит те же данные,
+// что и транзакция, которая его применяет
+type txReader struct {
+	tx *sql.Tx
+}
 
-	// 1. Создаем PR в таблице pull_requests
-	_, err := s.db.Exec(`
-		INSERT INTO pull_requests(
-			pull_request_id,
-			pull_request_name,
-			author_id,
-			status
+func (r txReader) GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error) {
+	return activeMembersByTeam(ctx, r.tx, teamName)
+}
+
+func (r txReader) GetTeamByUser(ctx context.Context, userID string) (*models.Team, error) {
+	return teamByUser(ctx, r.tx, userID)
+}
+
+func (r txReader) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return openReviewLoad(ctx, r.tx, userIDs)
+}
+
+// reassignOpenReviews - заблокировать открытые PR, где ревьюерами назначены уходящие пользователи
+// (leaving), подобрать им замену через planner и применить её. Отчёт строится по строкам,
+// которые изменил запрос, а не по плану
+func reassignOpenReviews(ctx context.Context, tx *sql.Tx, leaving []string, planner storage.ReassignmentPlanner, actor string) (*models.ReassignmentReport, error) {
+	// 1. Блокируем PR: до конца транзакции их статус и ревьюеров никто не изменит
+	_, err := tx.ExecContext(ctx, `
+		SELECT 1
+		FROM pull_requests
+		WHERE status = 'OPEN' AND pull_request_id IN (
+			SELECT pull_request_id FROM pull_requests_reviewers WHERE user_id = ANY($1)
 		)
-		VALUES ($1, $2, $3, 'OPEN')
-	`, pr.PullRequestID, pr.PullRequestName, pr.AuthorID)
+		ORDER BY pull_request_id
+		FOR UPDATE
+	`, pq.Array(leaving))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock pull requests: %w", err)
+	}
 
+	// 2. Читаем PR с ревьюерами уже под блокировкой
+	prs, err := openReviewsOfUsers(ctx, tx, leaving)
 	if err != nil {
-		return fmt.Errorf("%s: failed to create PR: %w", op, err)
+		return nil, err
 	}
 
-	// 2. Добавляем ревьюеров в таблицу pull_requests_reviewers
-	reviewerStmt, err := s.db.Prepare(`
-		INSERT INTO pull_requests_reviewers(pull_request_id, user_id)
-		VALUES ($1, $2)
-	`)
+	// 3. Подбираем замену по данным транзакции
+	moves, err := planner(ctx, txReader{tx: tx}, prs, leaving)
 	if err != nil {
-		return fmt.Errorf("%s: failed to prepare reviewer statement: %w", op, err)
+		return nil, err
 	}
-	defer reviewerStmt.Close()
 
-	// 3. Вставляем каждого ревьювера отдельной строкой
-	for _, reviewerID := range pr.AssignedReviewers {
-		_, err := reviewerStmt.Exec(pr.PullRequestID, reviewerID)
-		if err != nil {
-			return fmt.Errorf("%s: failed to add reviewer %s: %w", op, reviewerID, err)
-		}
+	// 4. Применяем
+	applied, err := applyReassignments(ctx, tx, moves, actor)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return storage.NewReassignmentReport(prs, leaving, applied), nil
 }
 
-// Функция находит всех участников команды автора и возвращает список активных
-func (s *Storage) GetActiveTeamMembers(authorID string) ([]string, error) {
-	const op = "storage.postgres.GetActiveTeamMember"
-	//	2.Получаем всех активных участников команды (кроме автора)
-	rows, err := s.db.Query(`
-    SELECT user_id
-    FROM users
-    WHERE team_name = (
-        SELECT team_name
-        FROM users
-        WHERE user_id = $1)
-    AND is_active = true
-    AND user_id != $1
-	`, authorID)
+// lockActiveUsers - заблокировать пользователей FOR SHARE до конца транзакции и проверить,
+// что все они активны. Если кого-то деактивируют параллельно, запрос дождётся коммита
+// и увидит уже неактивного пользователя (storage.ErrReviewerInactive)
+func lockActiveUsers(ctx context.Context, tx *sql.Tx, userIDs []string) error {
+	unique := slices.Compact(slices.Sorted(slices.Values(userIDs)))
 
+	var active int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM (
+			SELECT user_id
+			FROM users
+			WHERE user_id = ANY($1) AND is_active = true
+			ORDER BY user_id
+			FOR SHARE
+		) u
+	`, pq.Array(unique)).Scan(&active)
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get reviewrs: %w", op, err)
+		return fmt.Errorf("failed to lock reviewers: %w", err)
 	}
-	defer rows.Close()
 
-	var reviewrs []string
-	for rows.Next() {
-		var userID string
-		if err := rows.Scan(&userID); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
-		reviewrs = append(reviewrs, userID)
+	if active != len(unique) {
+		return storage.ErrReviewerInactive
 	}
-	return reviewrs, nil
+
+	return nil
 }
 
-// GetPullRequestReviewers - получить список ревьюеров PR
-func (s *Storage) GetPullRequestReviewers(pullRequestID string) ([]string, error) {
-	const op = "storage.postgres.GetPullRequestReviewers"
+// applyReassignments - заменить ревьюеров на открытых PR внутри транзакции одним запросом.
+// Возвращает применённые замены в порядке moves - по строкам, которые вставил запрос.
+// Замена пропускается, если PR уже не OPEN или старый ревьювер снят. Если новый ревьювер
+// деактивирован или уже назначен на PR - storage.ErrReviewerInactive / storage.ErrAlreadyAssigned
+func applyReassignments(ctx context.Context, tx *sql.Tx, moves []models.Reassignment, actor string) ([]models.Reassignment, error) {
+	if len(moves) == 0 {
+		return nil, nil
+	}
 
-	rows, err := s.db.Query(`
-		SELECT user_id
-		FROM pull_requests_reviewers
-		WHERE pull_request_id = $1
-	`, pullRequestID)
+	prIDs := make([]string, len(moves))
+	oldIDs := make([]string, len(moves))
+	newIDs := make([]string, len(moves))
+	for i, move := range moves {
+		prIDs[i] = move.PullRequestID
+		oldIDs[i] = move.OldReviewerID
+		newIDs[i] = move.NewReviewerID
+	}
+
+	// 1. Новые ревьюеры должны оставаться активными до конца транзакции
+	if err := lockActiveUsers(ctx, tx, newIDs); err != nil {
+		return nil, err
+	}
 
+	// 2. Заменяем и возвращаем пары (PR, старый ревьювер), которые действительно заменены
+	rows, err := tx.QueryContext(ctx, `
+		WITH moves AS (
+			SELECT * FROM unnest($1::text[], $2::text[], $3::text[])
+				AS m(pull_request_id, old_reviewer_id, new_reviewer_id)
+		),
+		deleted AS (
+			DELETE FROM pull_requests_reviewers prr
+			USING moves m, pull_requests pr
+			WHERE prr.pull_request_id = m.pull_request_id
+			AND prr.user_id = m.old_reviewer_id
+			AND pr.pull_request_id = prr.pull_request_id
+			AND pr.status = 'OPEN'
+			RETURNING prr.pull_request_id, prr.user_id, prr.assigned_at
+		),
+		inserted AS (
+			INSERT INTO pull_requests_reviewers (pull_request_id, user_id)
+			SELECT m.pull_request_id, m.new_reviewer_id
+			FROM moves m
+			INNER JOIN deleted d ON d.pull_request_id = m.pull_request_id AND d.user_id = m.old_reviewer_id
+			RETURNING pull_request_id, user_id
+		),
+		reassigned AS (
+			INSERT INTO reviewer_reassignments (pull_request_id, old_reviewer_id, new_reviewer_id, assigned_at)
+			SELECT m.pull_request_id, m.old_reviewer_id, m.new_reviewer_id, d.assigned_at
+			FROM moves m
+			INNER JOIN deleted d ON d.pull_request_id = m.pull_request_id AND d.user_id = m.old_reviewer_id
+		),
+		events AS (
+			INSERT INTO assignment_events (event_type, pull_request_id, actor, old_value, new_value)
+			SELECT $4, m.pull_request_id, $5, m.old_reviewer_id, m.new_reviewer_id
+			FROM moves m
+			INNER JOIN deleted d ON d.pull_request_id = m.pull_request_id AND d.user_id = m.old_reviewer_id
+		)
+		SELECT m.pull_request_id, m.old_reviewer_id
+		FROM moves m
+		INNER JOIN inserted i ON i.pull_request_id = m.pull_request_id AND i.user_id = m.new_reviewer_id
+	`, pq.Array(prIDs), pq.Array(oldIDs), pq.Array(newIDs), models.EventReassign, actor)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, reassignError(err)
 	}
 	defer rows.Close()
 
-	var reviewers []string
+	type key struct{ pullRequestID, oldReviewerID string }
+	done := make(map[key]bool, len(moves))
 	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		var k key
+		if err := rows.Scan(&k.pullRequestID, &k.oldReviewerID); err != nil {
+			return nil, err
 		}
-		reviewers = append(reviewers, reviewerID)
+		done[k] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, reassignError(err)
 	}
 
-	return reviewers, rows.Err()
-}
+	applied := make([]models.Reassignment, 0, len(done))
+	for _, move := range moves {
+		if done[key{move.PullRequestID, move.OldReviewerID}] {
+			applied = append(applied, move)
+		}
+	}
 
-// MergePullRequest - пометить PR как MERGED (идемпотентная операция)
-func (s *Storage) MergePullRequest(pullRequestID string) (*models.PullRequest, error) {
-	const op = "storage.postgres.MergePullRequest"
+	return applied, nil
+}
 
-	var pr models.PullRequest
+// reassignError - ошибка замены ревьювера: повторное назначение на PR - storage.ErrAlreadyAssigned
+func reassignError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return storage.ErrAlreadyAssigned
+	}
+	return fmt.Errorf("failed to reassign reviewers: %w", err)
+}
 
-	// UPDATE с RETURNING - обновляем и сразу получаем данные
-	err := s.db.QueryRow(`
-		UPDATE pull_requests
-		SET status = 'MERGED', merged_at = NOW()
-		WHERE pull_request_id = $1
-		RETURNING pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-	`, pullRequestID).Scan(
-		&pr.PullRequestID,
-		&pr.PullRequestName,
-		&pr.AuthorID,
-		&pr.Status,
-		&pr.CreatedAt,
-		&pr.MergedAt,
-	)
+// DeactivateTeamUsers - в одной транзакции деактивирует участников команды и переназначает
+// их открытые ревью. Замены подбирает planner по данным этой же транзакции, под блокировкой
+// пользователей и их PR. Если кто-то из пользователей не состоит в команде, ничего не меняется
+func (s *Storage) DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string, planner storage.ReassignmentPlanner, actor string) ([]models.User, *models.ReassignmentReport, error) {
+	const op = "storage.postgres.DeactivateTeamUsers"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	// Если PR не найден
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("%s: pull request not found", op)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	// Другие ошибки БД
+	// 1. Блокируем пользователей: назначение их ревьюерами в других транзакциях
+	// (проверка внешнего ключа) ждёт, пока эта не завершится
+	rows, err := tx.QueryContext(ctx, `
+		SELECT user_id, user_name, team_name, is_active
+		FROM users
+		WHERE team_name = $1 AND user_id = ANY($2)
+		ORDER BY user_id
+		FOR UPDATE
+	`, teamName, pq.Array(userIDs))
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0, len(userIDs))
+	var deactivated []string
+	var events []models.AssignmentEvent
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.UserName, &user.TeamName, &user.IsActive); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if user.IsActive {
+			deactivated = append(deactivated, user.UserID)
+			events = append(events, activityEvent(user.UserID, actor, true, false))
+			user.IsActive = false
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(users) != len(userIDs) {
+		return nil, nil, fmt.Errorf("%s: %w: team %s", op, storage.ErrNotMember, teamName)
+	}
+
+	// 2. Деактивируем
+	if len(deactivated) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE users SET is_active = false WHERE user_id = ANY($1)
+		`, pq.Array(deactivated)); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Получаем assigned_reviewers через отдельную функцию ✅
-	reviewers, err := s.GetPullRequestReviewers(pullRequestID)
+	// 3. Переназначаем открытые ревью
+	report, err := reassignOpenReviews(ctx, tx, userIDs, planner, actor)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	pr.AssignedReviewers = reviewers
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
 
-	return &pr, nil
+	return users, report, nil
 }
 
-// GetPullRequestByID - получить PR по ID с проверкой статуса
-func (s *Storage) GetPullRequestByID(pullRequestID string) (*models.PullRequest, error) {
-	const op = "storage.postgres.GetPullRequestByID"
-
-	var pr models.PullRequest
+// GetUser - получить пользователя по id
+func (s *Storage) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	const op = "storage.postgres.GetUser"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	// Получаем основную информацию о PR
-	err := s.db.QueryRow(`
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-		FROM pull_requests
-		WHERE pull_request_id = $1
-	`, pullRequestID).Scan(
-		&pr.PullRequestID,
-		&pr.PullRequestName,
-		&pr.AuthorID,
-		&pr.Status,
-		&pr.CreatedAt,
-		&pr.MergedAt,
-	)
+	var user models.User
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id, user_name, COALESCE(team_name, ''), is_active
+		FROM users
+		WHERE user_id = $1
+	`, userID).Scan(&user.UserID, &user.UserName, &user.TeamName, &user.IsActive)
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("%s: pull request not found", op)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Получаем список ревьюеров
-	reviewers, err := s.GetPullRequestReviewers(pullRequestID)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+	return &user, nil
+}
+
+// CreatePullRequest - функция создает pull request и назначает ревьюеров в одной транзакции.
+// Возвращает storage.ErrPRExists, если PR с таким id уже есть, storage.ErrAuthorNotFound,
+// если автора нет, и storage.ErrReviewerInactive, если кого-то из ревьюеров уже деактивировали
+func (s *Storage) CreatePullRequest(ctx context.Context, pr models.PullRequest, actor string) error {
+	const op = "storage.postgres.CreatePullRequest"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// 1. Создаем PR в таблице pull_requests
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pull_requests(
+			pull_request_id,
+			pull_request_name,
+			author_id,
+			status
+		)
+		VALUES ($1, $2, $3, $4)
+	`, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, cmp.Or(pr.Status, models.StatusOpen))
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == uniqueViolation:
+			return fmt.Errorf("%s: %w", op, storage.ErrPRExists)
+		case pqErr.Code == foreignKeyViolation && pqErr.Constraint == "pull_requests_author_id_fkey":
+			return fmt.Errorf("%s: %w", op, storage.ErrAuthorNotFound)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to create PR: %w", op, err)
+	}
+
+	// 2. Добавляем ревьюеров в таблицу pull_requests_reviewers одним запросом.
+	// Они должны оставаться активными до конца транзакции: параллельная деактивация либо дождётся
+	// коммита и переназначит их ревью на этом PR, либо завершится раньше - тогда ErrReviewerInactive
+	if len(pr.AssignedReviewers) > 0 {
+		if err := lockActiveUsers(ctx, tx, pr.AssignedReviewers); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_requests_reviewers(pull_request_id, user_id)
+			SELECT $1, unnest($2::text[])
+		`, pr.PullRequestID, pq.Array(pr.AssignedReviewers))
+
+		if err != nil {
+			return fmt.Errorf("%s: failed to add reviewers: %w", op, err)
+		}
+	}
+
+	// 3. Записываем назначения в журнал
+	events := make([]models.AssignmentEvent, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		events = append(events, models.AssignmentEvent{
+			Type:          models.EventAssign,
+			PullRequestID: pr.PullRequestID,
+			Actor:         actor,
+			NewValue:      reviewerID,
+		})
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetActiveMembersByTeam - получить активных участников команды
+func (s *Storage) GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error) {
+	const op = "storage.postgres.GetActiveMembersByTeam"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	members, err := activeMembersByTeam(ctx, s.db, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// activeMembersByTeam - активные участники команды по возрастанию user_id
+func activeMembersByTeam(ctx context.Context, db queryer, teamName string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT user_id
+		FROM users
+		WHERE team_name = $1 AND is_active = true
+		ORDER BY user_id
+	`, teamName)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	var members []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		members = append(members, userID)
+	}
+
+	return members, rows.Err()
+}
+
+// GetOpenReviewLoad - количество назначений на открытые PR для каждого из пользователей
+func (s *Storage) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const op = "storage.postgres.GetOpenReviewLoad"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	loads, err := openReviewLoad(ctx, s.db, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return loads, nil
+}
+
+// openReviewLoad - количество назначений на открытые PR по user_id
+func openReviewLoad(ctx context.Context, db queryer, userIDs []string) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT prr.user_id, COUNT(*)
+		FROM pull_requests_reviewers prr
+		INNER JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		WHERE prr.user_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY prr.user_id
+	`, pq.Array(userIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	loads := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var load int
+		if err := rows.Scan(&userID, &load); err != nil {
+			return nil, err
+		}
+		loads[userID] = load
+	}
+
+	return loads, rows.Err()
+}
+
+// GetPullRequestReviewers - получить список ревьюеров PR
+func (s *Storage) GetPullRequestReviewers(ctx context.Context, pullRequestID string) ([]string, error) {
+	const op = "storage.postgres.GetPullRequestReviewers"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id
+		FROM pull_requests_reviewers
+		WHERE pull_request_id = $1
+	`, pullRequestID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var reviewers []string
+	for rows.Next() {
+		var reviewerID string
+		if err := rows.Scan(&reviewerID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reviewers = append(reviewers, reviewerID)
+	}
+
+	return reviewers, rows.Err()
+}
+
+// MergePullRequest - пометить PR как MERGED (идемпотентная операция: повторный вызов
+// не меняет merged_at и не пишет событие в журнал). Перед слиянием проверяется политика
+// команды автора; с override PR сливается в обход неё, а нарушенные условия и причина пишутся в журнал
+func (s *Storage) MergePullRequest(ctx context.Context, pullRequestID string, override *models.MergeOverride, actor string) (*models.PullRequest, error) {
+	const op = "storage.postgres.MergePullRequest"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем PR и проверяем, что его можно слить (повторное слияние допустимо)
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE
+	`, pullRequestID).Scan(&status)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if status != models.StatusMerged {
+		if err := models.TransitionMerge.Check(status); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Проверяем политику слияния команды автора до изменения статуса
+		var authorID string
+		var policy models.MergePolicy
+		err = tx.QueryRowContext(ctx, `
+			SELECT
+				pr.author_id,
+				COALESCE(t.required_approvals, 0),
+				COALESCE(t.no_changes_requested, false),
+				COALESCE(t.author_not_last_approver, false)
+			FROM pull_requests pr
+			LEFT JOIN users u ON u.user_id = pr.author_id
+			LEFT JOIN teams t ON t.team_name = u.team_name
+			WHERE pr.pull_request_id = $1
+		`, pullRequestID).Scan(&authorID, &policy.MinApprovals, &policy.NoChangesRequested, &policy.AuthorNotLastApprover)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get merge policy: %w", op, err)
+		}
+
+		reviews, err := getReviews(ctx, tx, pullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if unmet := policy.Check(authorID, reviews); len(unmet) > 0 {
+			if override == nil {
+				return nil, fmt.Errorf("%s: %w", op, &models.MergePolicyError{Unmet: unmet})
+			}
+
+			// Слияние в обход политики записываем в журнал вместе с причиной
+			conditions := make([]string, len(unmet))
+			for i, c := range unmet {
+				conditions[i] = c.Condition
+			}
+			err = recordEvents(ctx, tx, []models.AssignmentEvent{{
+				Type:          models.EventOverride,
+				PullRequestID: pullRequestID,
+				Actor:         actor,
+				OldValue:      strings.Join(conditions, ","),
+				Comment:       override.Reason,
+			}})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	var pr models.PullRequest
+	var oldStatus string
+
+	// UPDATE с RETURNING - обновляем и сразу получаем данные (во FROM - строка до обновления)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE pull_requests p
+		SET status = 'MERGED', merged_at = COALESCE(p.merged_at, NOW())
+		FROM pull_requests prev
+		WHERE p.pull_request_id = $1 AND prev.pull_request_id = p.pull_request_id
+		RETURNING p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, p.merged_at, prev.status
+	`, pullRequestID).Scan(
+		&pr.PullRequestID,
+		&pr.PullRequestName,
+		&pr.AuthorID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&oldStatus,
+	)
+
+	// Если PR не найден
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	// Другие ошибки БД
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if oldStatus != pr.Status {
+		err = recordEvents(ctx, tx, []models.AssignmentEvent{{
+			Type:          models.EventMerge,
+			PullRequestID: pullRequestID,
+			Actor:         actor,
+			OldValue:      oldStatus,
+			NewValue:      pr.Status,
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	// Получаем assigned_reviewers через отдельную функцию
+	reviewers, err := s.GetPullRequestReviewers(ctx, pullRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pr.AssignedReviewers = reviewers
+
+	return &pr, nil
+}
+
+// ChangePullRequestStatus - перевести PR в новый статус по переходу жизненного цикла и назначить
+// ревьюеров reviewers (если переход открывает PR). Текущий статус проверяется под блокировкой строки;
+// недопустимый переход возвращает ошибку, обёрнутую в models.ErrInvalidTransition.
+// Неактивных ревьюеров открываемого PR (деактивированных, пока он был закрыт) planner заменяет
+// в той же транзакции; отчёт о замене возвращается, только если такие ревьюеры были
+func (s *Storage) ChangePullRequestStatus(ctx context.Context, pullRequestID string, transition models.Transition, reviewers []string, planner storage.ReassignmentPlanner, actor string) (*models.PullRequest, *models.ReassignmentReport, error) {
+	const op = "storage.postgres.ChangePullRequestStatus"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// 1. Блокируем PR и проверяем переход
+	var status, authorID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, author_id FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE
+	`, pullRequestID).Scan(&status, &authorID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := transition.Check(status); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 2. Меняем статус; closed_at выставляется при закрытии и сбрасывается при переоткрытии
+	_, err = tx.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET status = $2, closed_at = CASE WHEN $2 = 'CLOSED' THEN now() END
+		WHERE pull_request_id = $1
+	`, pullRequestID, transition.To)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to update status: %w", op, err)
+	}
+
+	// 3. Назначаем ревьюеров; они должны оставаться активными до конца транзакции.
+	// Уже назначенные пропускаются, поэтому в журнал попадают только реально добавленные
+	var assigned []string
+	if len(reviewers) > 0 {
+		if err := lockActiveUsers(ctx, tx, reviewers); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			INSERT INTO pull_requests_reviewers (pull_request_id, user_id)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT DO NOTHING
+			RETURNING user_id
+		`, pullRequestID, pq.Array(reviewers))
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to add reviewers: %w", op, err)
+		}
+
+		for rows.Next() {
+			var reviewerID string
+			if err := rows.Scan(&reviewerID); err != nil {
+				rows.Close()
+				return nil, nil, fmt.Errorf("%s: failed to scan reviewer: %w", op, err)
+			}
+			assigned = append(assigned, reviewerID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to add reviewers: %w", op, err)
+		}
+	}
+
+	// 4. Записываем переход и назначения в журнал
+	events := []models.AssignmentEvent{{
+		Type:          transition.Name,
+		PullRequestID: pullRequestID,
+		Actor:         actor,
+		OldValue:      status,
+		NewValue:      transition.To,
+	}}
+	for _, reviewerID := range assigned {
+		events = append(events, models.AssignmentEvent{
+			Type:          models.EventAssign,
+			PullRequestID: pullRequestID,
+			Actor:         actor,
+			NewValue:      reviewerID,
+		})
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 5. Заменяем неактивных ревьюеров открываемого PR
+	var report *models.ReassignmentReport
+	if transition.To == models.StatusOpen {
+		pr := models.PullRequest{PullRequestID: pullRequestID, AuthorID: authorID, Status: transition.To}
+		report, err = replaceInactiveReviewers(ctx, tx, pr, planner, actor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	result, err := s.GetPullRequestByID(ctx, pullRequestID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, report, nil
+}
+
+// replaceInactiveReviewers - заменить через planner неактивных ревьюеров PR. Ревьюеры блокируются
+// FOR SHARE, поэтому параллельная деактивация дождётся конца транзакции, а уже деактивированные
+// будут видны как неактивные. Возвращает nil, если неактивных ревьюеров нет
+func replaceInactiveReviewers(ctx context.Context, tx *sql.Tx, pr models.PullRequest, planner storage.ReassignmentPlanner, actor string) (*models.ReassignmentReport, error) {
+	// 1. Ревьюеры PR и их активность под блокировкой
+	rows, err := tx.QueryContext(ctx, `
+		SELECT u.user_id, u.is_active
+		FROM pull_requests_reviewers prr
+		INNER JOIN users u ON u.user_id = prr.user_id
+		WHERE prr.pull_request_id = $1
+		ORDER BY u.user_id
+		FOR SHARE OF u
+	`, pr.PullRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	var inactive []string
+	for rows.Next() {
+		var userID string
+		var isActive bool
+		if err := rows.Scan(&userID, &isActive); err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+		if !isActive {
+			inactive = append(inactive, userID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(inactive) == 0 {
+		return nil, nil
+	}
+
+	// 2. Подбираем замену по данным транзакции и применяем
+	prs := []models.PullRequest{pr}
+	moves, err := planner(ctx, txReader{tx: tx}, prs, inactive)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := applyReassignments(ctx, tx, moves, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.NewReassignmentReport(prs, inactive, applied), nil
+}
+
+// SubmitReview - записать решение ревьювера по PR. Решение COMMENT не меняет состояние ревью,
+// но, как и остальные, попадает в журнал вместе с комментарием
+func (s *Storage) SubmitReview(ctx context.Context, pullRequestID, reviewerID, decision, comment, actor string) error {
+	const op = "storage.postgres.SubmitReview"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	state, ok := models.ReviewStateOf(decision)
+	if !ok {
+		return fmt.Errorf("%s: unknown decision %s", op, decision)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Обновляем состояние только на открытом PR (во FROM - строка до обновления)
+	var oldState string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE pull_requests_reviewers prr
+		SET review_state = COALESCE(NULLIF($3, ''), prr.review_state), reviewed_at = now(), reviewed_by = $4
+		FROM pull_requests_reviewers prev, pull_requests pr
+		WHERE prr.pull_request_id = $1 AND prr.user_id = $2
+		AND prev.pull_request_id = prr.pull_request_id AND prev.user_id = prr.user_id
+		AND pr.pull_request_id = prr.pull_request_id AND pr.status = 'OPEN'
+		RETURNING prev.review_state
+	`, pullRequestID, reviewerID, state, actor).Scan(&oldState)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotAssigned)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = recordEvents(ctx, tx, []models.AssignmentEvent{{
+		Type:          models.EventReview,
+		PullRequestID: pullRequestID,
+		UserID:        reviewerID,
+		Actor:         actor,
+		OldValue:      oldState,
+		NewValue:      cmp.Or(state, oldState),
+		Comment:       comment,
+	}})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetPullRequestReviews - состояния ревью всех назначенных на PR ревьюеров
+func (s *Storage) GetPullRequestReviews(ctx context.Context, pullRequestID string) ([]models.Review, error) {
+	const op = "storage.postgres.GetPullRequestReviews"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	reviews, err := getReviews(ctx, s.db, pullRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
+
+// getReviews - состояния ревью PR (в том числе внутри транзакции)
+func getReviews(ctx context.Context, db queryer, pullRequestID string) ([]models.Review, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT user_id, review_state, reviewed_at, COALESCE(reviewed_by, '')
+		FROM pull_requests_reviewers
+		WHERE pull_request_id = $1
+		ORDER BY user_id
+	`, pullRequestID)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(&review.UserID, &review.State, &review.ReviewedAt, &review.ReviewedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// GetPullRequestByID - получить PR по ID с проверкой статуса
+func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error) {
+	const op = "storage.postgres.GetPullRequestByID"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var pr models.PullRequest
+
+	// Получаем основную информацию о PR
+	err := s.db.QueryRowContext(ctx, `
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at
+		FROM pull_requests
+		WHERE pull_request_id = $1
+	`, pullRequestID).Scan(
+		&pr.PullRequestID,
+		&pr.PullRequestName,
+		&pr.AuthorID,
+		&pr.Status,
+		&pr.CreatedAt,
+		&pr.MergedAt,
+		&pr.ClosedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Получаем список ревьюеров
+	reviewers, err := s.GetPullRequestReviewers(ctx, pullRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	pr.AssignedReviewers = reviewers
 	return &pr, nil
 }
 
-// ReassignReviewer - заменить ревьювера на другого в PR
-func (s *Storage) ReassignReviewer(pullRequestID, oldReviewerID, newReviewerID string) error {
+// ListPullRequests - страница PR по фильтру, от новых к старым, вместе с ревьюерами.
+// Возвращает курсор следующей страницы или nil, если страница последняя
+func (s *Storage) ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequest, *models.PageCursor, error) {
+	const op = "storage.postgres.ListPullRequests"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var afterCreatedAt *time.Time
+	var afterID string
+	if filter.After != nil {
+		afterCreatedAt, afterID = &filter.After.CreatedAt, filter.After.PullRequestID
+	}
+
+	// Запрашиваем на одну строку больше, чтобы понять, есть ли следующая страница
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			pr.pull_request_id,
+			pr.pull_request_name,
+			pr.author_id,
+			pr.status,
+			pr.created_at,
+			pr.merged_at,
+			pr.closed_at,
+			COALESCE(array_agg(prr.user_id ORDER BY prr.user_id) FILTER (WHERE prr.user_id IS NOT NULL), '{}')
+		FROM pull_requests pr
+		LEFT JOIN users u ON u.user_id = pr.author_id
+		LEFT JOIN pull_requests_reviewers prr ON prr.pull_request_id = pr.pull_request_id
+		WHERE ($1 = '' OR pr.status = $1)
+		AND ($2 = '' OR pr.author_id = $2)
+		AND ($3 = '' OR u.team_name = $3)
+		AND ($4 = '' OR EXISTS (
+			SELECT 1 FROM pull_requests_reviewers r
+			WHERE r.pull_request_id = pr.pull_request_id AND r.user_id = $4
+		))
+		AND ($5::timestamptz IS NULL OR pr.created_at >= $5)
+		AND ($6::timestamptz IS NULL OR pr.created_at < $6)
+		AND ($7::timestamptz IS NULL OR pr.merged_at >= $7)
+		AND ($8::timestamptz IS NULL OR pr.merged_at < $8)
+		AND ($9::timestamptz IS NULL OR (pr.created_at, pr.pull_request_id) < ($9, $10::text))
+		GROUP BY pr.pull_request_id
+		ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+		LIMIT $11
+	`, filter.Status, filter.AuthorID, filter.TeamName, filter.ReviewerID,
+		filter.CreatedFrom, filter.CreatedTo, filter.MergedFrom, filter.MergedTo,
+		afterCreatedAt, afterID, filter.Limit+1)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var pullRequests []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+			pq.Array(&pr.AssignedReviewers),
+		); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pullRequests = append(pullRequests, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(pullRequests) <= filter.Limit {
+		return pullRequests, nil, nil
+	}
+
+	pullRequests = pullRequests[:filter.Limit]
+	last := pullRequests[len(pullRequests)-1]
+	return pullRequests, &models.PageCursor{CreatedAt: *last.CreatedAt, PullRequestID: last.PullRequestID}, nil
+}
+
+// ReassignReviewer - заменить ревьювера на другого в PR. Статус PR и активность нового ревьювера
+// проверяются под блокировкой строк, поэтому проверки обработчика не могут устареть к моменту записи
+func (s *Storage) ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID, newReviewerID, actor string) error {
 	const op = "storage.postgres.ReassignReviewer"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
 	// Используем транзакцию для атомарности
-	tx, err := s.db.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
 	}
 	defer tx.Rollback()
 
-	// 1. Удаляем старого ревьювера
-	_, err = tx.Exec(`
+	// 1. Блокируем PR: до конца транзакции его статус и ревьюеров никто не изменит
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE
+	`, pullRequestID).Scan(&status)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, storage.ErrPRNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch status {
+	case models.StatusOpen:
+	case models.StatusMerged:
+		return fmt.Errorf("%s: %w", op, storage.ErrPRMerged)
+	default:
+		return fmt.Errorf("%s: %w", op, storage.ErrPRNotOpen)
+	}
+
+	// 2. Новый ревьювер должен оставаться активным до конца транзакции
+	if err := lockActiveUsers(ctx, tx, []string{newReviewerID}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 3. Удаляем старого ревьювера (запоминаем, когда он был назначен - для статистики)
+	var assignedAt time.Time
+	err = tx.QueryRowContext(ctx, `
 		DELETE FROM pull_requests_reviewers
 		WHERE pull_request_id = $1 AND user_id = $2
-	`, pullRequestID, oldReviewerID)
+		RETURNING assigned_at
+	`, pullRequestID, oldReviewerID).Scan(&assignedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotAssigned)
+	}
 
 	if err != nil {
 		return fmt.Errorf("%s: failed to delete old reviewer: %w", op, err)
 	}
 
-	// 2. Добавляем нового ревьювера
-	_, err = tx.Exec(`
+	// 4. Добавляем нового ревьювера
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO pull_requests_reviewers (pull_request_id, user_id)
 		VALUES ($1, $2)
 	`, pullRequestID, newReviewerID)
 
 	if err != nil {
-		return fmt.Errorf("%s: failed to insert new reviewer: %w", op, err)
+		return fmt.Errorf("%s: %w", op, reassignError(err))
+	}
+
+	// 5. Записываем факт переназначения
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reviewer_reassignments (pull_request_id, old_reviewer_id, new_reviewer_id, assigned_at)
+		VALUES ($1, $2, $3, $4)
+	`, pullRequestID, oldReviewerID, newReviewerID, assignedAt)
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to record reassignment: %w", op, err)
+	}
+
+	err = recordEvents(ctx, tx, []models.AssignmentEvent{{
+		Type:          models.EventReassign,
+		PullRequestID: pullRequestID,
+		Actor:         actor,
+		OldValue:      oldReviewerID,
+		NewValue:      newReviewerID,
+	}})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	// Коммитим транзакцию
@@ -487,11 +1709,13 @@ func (s *Storage) ReassignReviewer(pullRequestID, oldReviewerID, newReviewerID s
 }
 
 // IsReviewerAssigned - проверить, назначен ли пользователь ревьювером на PR
-func (s *Storage) IsReviewerAssigned(pullRequestID, userID string) (bool, error) {
+func (s *Storage) IsReviewerAssigned(ctx context.Context, pullRequestID, userID string) (bool, error) {
 	const op = "storage.postgres.IsReviewerAssigned"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
 	var count int
-	err := s.db.QueryRow(`
+	err := s.db.QueryRowContext(ctx, `
 		SELECT COUNT(*)
 		FROM pull_requests_reviewers
 		WHERE pull_request_id = $1 AND user_id = $2
@@ -504,32 +1728,102 @@ func (s *Storage) IsReviewerAssigned(pullRequestID, userID string) (bool, error)
 	return count > 0, nil
 }
 
-// GetUserAssignedPullRequests - получить все PR'ы, где пользователь назначен ревьювером
-func (s *Storage) GetUserAssignedPullRequests(userID string) ([]models.PullRequestShort, error) {
+// GetUserAssignedPullRequests - страница PR, где пользователь назначен ревьювером, от новых к старым.
+// Возвращает курсор следующей страницы или nil, если страница последняя
+func (s *Storage) GetUserAssignedPullRequests(ctx context.Context, filter models.ReviewFilter) ([]models.PullRequestShort, *models.PageCursor, error) {
 	const op = "storage.postgres.GetUserAssignedPullRequests"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	rows, err := s.db.Query(`
+	var afterCreatedAt *time.Time
+	var afterID string
+	if filter.After != nil {
+		afterCreatedAt, afterID = &filter.After.CreatedAt, filter.After.PullRequestID
+	}
+
+	// Запрашиваем на одну строку больше, чтобы понять, есть ли следующая страница
+	rows, err := s.db.QueryContext(ctx, `
 		SELECT 
 			pr.pull_request_id,
 			pr.pull_request_name,
 			pr.author_id,
-			pr.status
+			pr.status,
+			pr.created_at
 		FROM pull_requests pr
 		INNER JOIN pull_requests_reviewers prr ON pr.pull_request_id = prr.pull_request_id
 		WHERE prr.user_id = $1
-		ORDER BY pr.created_at DESC
-	`, userID)
+		AND ($2 = '' OR pr.status = $2)
+		AND ($3::timestamptz IS NULL OR (pr.created_at, pr.pull_request_id) < ($3, $4::text))
+		ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+		LIMIT $5
+	`, filter.UserID, filter.Status, afterCreatedAt, afterID, filter.Limit+1)
 
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer rows.Close()
 
 	var pullRequests []models.PullRequestShort
+	var createdAt []time.Time
 	for rows.Next() {
 		var pr models.PullRequestShort
-		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		var created time.Time
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &created); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pullRequests = append(pullRequests, pr)
+		createdAt = append(createdAt, created)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(pullRequests) <= filter.Limit {
+		return pullRequests, nil, nil
+	}
+
+	pullRequests = pullRequests[:filter.Limit]
+	last := len(pullRequests) - 1
+	return pullRequests, &models.PageCursor{CreatedAt: createdAt[last], PullRequestID: pullRequests[last].PullRequestID}, nil
+}
+
+// openReviewsOfUsers - открытые PR, где ревьювером назначен кто-то из пользователей, от старых к новым
+func openReviewsOfUsers(ctx context.Context, db queryer, userIDs []string) ([]models.PullRequest, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			pr.pull_request_id,
+			pr.pull_request_name,
+			pr.author_id,
+			pr.status,
+			pr.created_at,
+			array_agg(prr.user_id ORDER BY prr.user_id)
+		FROM pull_requests pr
+		INNER JOIN pull_requests_reviewers prr ON prr.pull_request_id = pr.pull_request_id
+		WHERE pr.status = 'OPEN' AND pr.pull_request_id IN (
+			SELECT pull_request_id FROM pull_requests_reviewers WHERE user_id = ANY($1)
+		)
+		GROUP BY pr.pull_request_id
+		ORDER BY pr.created_at, pr.pull_request_id
+	`, pq.Array(userIDs))
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pullRequests []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			pq.Array(&pr.AssignedReviewers),
+		); err != nil {
+			return nil, err
 		}
 		pullRequests = append(pullRequests, pr)
 	}
@@ -537,12 +1831,63 @@ func (s *Storage) GetUserAssignedPullRequests(userID string) ([]models.PullReque
 	return pullRequests, rows.Err()
 }
 
+// GetPullRequestHistory - журнал событий PR в порядке записи
+func (s *Storage) GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]models.AssignmentEvent, error) {
+	const op = "storage.postgres.GetPullRequestHistory"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			id,
+			event_type,
+			COALESCE(pull_request_id, ''),
+			COALESCE(user_id, ''),
+			actor,
+			COALESCE(old_value, ''),
+			COALESCE(new_value, ''),
+			COALESCE(comment, ''),
+			created_at
+		FROM assignment_events
+		WHERE pull_request_id = $1
+		ORDER BY id
+	`, pullRequestID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []models.AssignmentEvent
+	for rows.Next() {
+		var event models.AssignmentEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.PullRequestID,
+			&event.UserID,
+			&event.Actor,
+			&event.OldValue,
+			&event.NewValue,
+			&event.Comment,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
 // CheckUserExists - проверить, существует ли пользователь
-func (s *Storage) CheckUserExists(userID string) error {
+func (s *Storage) CheckUserExists(ctx context.Context, userID string) error {
 	const op = "storage.postgres.CheckUserExists"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
 	var exists bool
-	err := s.db.QueryRow(`
+	err := s.db.QueryRowContext(ctx, `
 		SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)
 	`, userID).Scan(&exists)
 
@@ -551,8 +1896,468 @@ func (s *Storage) CheckUserExists(userID string) error {
 	}
 
 	if !exists {
-		return fmt.Errorf("%s: user not found", op)
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// GetReviewerStats - статистика ревьюеров: назначения (всего, на открытых и на слитых PR)
+// считаются по времени назначения, переназначения на других - по времени переназначения.
+// Пустые поля фильтра не ограничивают выборку
+func (s *Storage) GetReviewerStats(ctx context.Context, filter models.StatsFilter) ([]models.ReviewerStats, error) {
+	const op = "storage.postgres.GetReviewerStats"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			u.user_id,
+			u.user_name,
+			COALESCE(u.team_name, ''),
+			COALESCE(a.current, 0) + COALESCE(r.away_assigned, 0),
+			COALESCE(a.open, 0),
+			COALESCE(a.merged, 0),
+			COALESCE(r.away, 0)
+		FROM users u
+		LEFT JOIN (
+			SELECT
+				prr.user_id,
+				COUNT(*) AS current,
+				COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open,
+				COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS merged
+			FROM pull_requests_reviewers prr
+			INNER JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+			WHERE ($2::timestamptz IS NULL OR prr.assigned_at >= $2)
+			AND ($3::timestamptz IS NULL OR prr.assigned_at < $3)
+			GROUP BY prr.user_id
+		) a ON a.user_id = u.user_id
+		LEFT JOIN (
+			SELECT
+				old_reviewer_id AS user_id,
+				COUNT(*) FILTER (
+					WHERE ($2::timestamptz IS NULL OR assigned_at >= $2)
+					AND ($3::timestamptz IS NULL OR assigned_at < $3)
+				) AS away_assigned,
+				COUNT(*) FILTER (
+					WHERE ($2::timestamptz IS NULL OR reassigned_at >= $2)
+					AND ($3::timestamptz IS NULL OR reassigned_at < $3)
+				) AS away
+			FROM reviewer_reassignments
+			GROUP BY old_reviewer_id
+		) r ON r.user_id = u.user_id
+		WHERE ($1 = '' OR u.team_name = $1)
+		ORDER BY u.user_id
+	`, filter.TeamName, filter.From, filter.To)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var stats []models.ReviewerStats
+	for rows.Next() {
+		var st models.ReviewerStats
+		if err := rows.Scan(
+			&st.UserID,
+			&st.UserName,
+			&st.TeamName,
+			&st.TotalAssignments,
+			&st.OpenAssignments,
+			&st.MergedReviews,
+			&st.ReassignedAway,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetPullRequestStats - статистика PR: количество по статусам для PR, созданных за период,
+// число слияний и медиана/p90 времени до слияния для PR, слитых за период.
+// Считается одним проходом на каждую часть через GROUPING SETS: всего, по командам и по авторам
+func (s *Storage) GetPullRequestStats(ctx context.Context, filter models.StatsFilter) (*models.PullRequestStatsReport, error) {
+	const op = "storage.postgres.GetPullRequestStats"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// Ключ группы: уровень определяется по GROUPING(), а не по пустым team/author,
+	// иначе авторы без команды (team_name IS NULL) смешались бы с итогом
+	type groupKey struct {
+		teamGrouped, authorGrouped bool
+		team, author               string
+	}
+	groups := make(map[groupKey]*models.PullRequestStats)
+	group := func(teamGrouped, authorGrouped int, team, author string) *models.PullRequestStats {
+		key := groupKey{teamGrouped: teamGrouped == 1, authorGrouped: authorGrouped == 1}
+		if !key.teamGrouped {
+			key.team = team
+		}
+		if !key.authorGrouped {
+			key.author = author
+		}
+		st, ok := groups[key]
+		if !ok {
+			st = &models.PullRequestStats{ByStatus: make(map[string]int)}
+			groups[key] = st
+		}
+		return st
+	}
+
+	// 1. Количество PR по статусам среди созданных за период
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			GROUPING(u.team_name),
+			GROUPING(pr.author_id),
+			COALESCE(u.team_name, ''),
+			COALESCE(pr.author_id, ''),
+			pr.status,
+			COUNT(*)
+		FROM pull_requests pr
+		INNER JOIN users u ON u.user_id = pr.author_id
+		WHERE ($1 = '' OR u.team_name = $1)
+		AND ($2::timestamptz IS NULL OR pr.created_at >= $2)
+		AND ($3::timestamptz IS NULL OR pr.created_at < $3)
+		GROUP BY GROUPING SETS ((pr.status), (u.team_name, pr.status), (u.team_name, pr.author_id, pr.status))
+	`, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamGrouped, authorGrouped, count int
+		var team, author, status string
+		if err := rows.Scan(&teamGrouped, &authorGrouped, &team, &author, &status, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		st := group(teamGrouped, authorGrouped, team, author)
+		st.ByStatus[status] = count
+		st.Created += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 2. Слияния за период и время до слияния
+	mergeRows, err := s.db.QueryContext(ctx, `
+		SELECT
+			GROUPING(u.team_name),
+			GROUPING(pr.author_id),
+			COALESCE(u.team_name, ''),
+			COALESCE(pr.author_id, ''),
+			COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM pr.merged_at - pr.created_at))
+		FROM pull_requests pr
+		INNER JOIN users u ON u.user_id = pr.author_id
+		WHERE pr.merged_at IS NOT NULL
+		AND ($1 = '' OR u.team_name = $1)
+		AND ($2::timestamptz IS NULL OR pr.merged_at >= $2)
+		AND ($3::timestamptz IS NULL OR pr.merged_at < $3)
+		GROUP BY GROUPING SETS ((), (u.team_name), (u.team_name, pr.author_id))
+	`, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer mergeRows.Close()
+
+	for mergeRows.Next() {
+		var teamGrouped, authorGrouped, merged int
+		var team, author string
+		var median, p90 sql.NullFloat64
+		if err := mergeRows.Scan(&teamGrouped, &authorGrouped, &team, &author, &merged, &median, &p90); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		st := group(teamGrouped, authorGrouped, team, author)
+		st.Merged = merged
+		if median.Valid {
+			st.MedianTimeToMergeSeconds = &median.Float64
+		}
+		if p90.Valid {
+			st.P90TimeToMergeSeconds = &p90.Float64
+		}
+	}
+	if err := mergeRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 3. Раскладываем группы по уровням
+	report := &models.PullRequestStatsReport{
+		Total:   models.PullRequestStats{ByStatus: make(map[string]int)},
+		Teams:   []models.TeamPullRequestStats{},
+		Authors: []models.AuthorPullRequestStats{},
+	}
+	for key, st := range groups {
+		switch {
+		case key.teamGrouped:
+			report.Total = *st
+		case key.authorGrouped:
+			report.Teams = append(report.Teams, models.TeamPullRequestStats{TeamName: key.team, PullRequestStats: *st})
+		default:
+			report.Authors = append(report.Authors, models.AuthorPullRequestStats{AuthorID: key.author, TeamName: key.team, PullRequestStats: *st})
+		}
+	}
+
+	slices.SortFunc(report.Teams, func(a, b models.TeamPullRequestStats) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	slices.SortFunc(report.Authors, func(a, b models.AuthorPullRequestStats) int {
+		return strings.Compare(a.AuthorID, b.AuthorID)
+	})
+
+	return report, nil
+}
+
+// GetOpenPullRequestsReport - открытые PR по командам авторов (всего и без активных ревьюеров)
+// и число открытых PR на каждом активном ревьювере. Команды и активные пользователи
+// без открытых PR попадают в отчёт с нулями; неактивные пользователи в отчёт не попадают
+func (s *Storage) GetOpenPullRequestsReport(ctx context.Context) (*models.OpenPullRequestsReport, error) {
+	const op = "storage.postgres.GetOpenPullRequestsReport"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// 1. Открытые PR по командам авторов. PR застрял, если на нём не осталось активных ревьюеров:
+	// деактивация без кандидата на замену оставляет на PR неактивного ревьювера
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			t.team_name,
+			COUNT(pr.pull_request_id),
+			COUNT(pr.pull_request_id) FILTER (
+				WHERE NOT EXISTS (
+					SELECT 1
+					FROM pull_requests_reviewers prr
+					JOIN users ru ON ru.user_id = prr.user_id
+					WHERE prr.pull_request_id = pr.pull_request_id AND ru.is_active
+				)
+			)
+		FROM teams t
+		LEFT JOIN users u ON u.team_name = t.team_name
+		LEFT JOIN pull_requests pr ON pr.author_id = u.user_id AND pr.status = 'OPEN'
+		GROUP BY t.team_name
+		ORDER BY t.team_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	report := &models.OpenPullRequestsReport{}
+	for rows.Next() {
+		var team models.TeamOpenPullRequests
+		if err := rows.Scan(&team.TeamName, &team.Open, &team.WithoutReviewers); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Teams = append(report.Teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 2. Открытые PR на активных ревьюерах: неактивные не попадают в метрики,
+	// чтобы число серий не росло с каждым ушедшим сотрудником
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT u.user_id, COALESCE(u.team_name, ''), COUNT(pr.pull_request_id)
+		FROM users u
+		LEFT JOIN pull_requests_reviewers prr ON prr.user_id = u.user_id
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id AND pr.status = 'OPEN'
+		WHERE u.is_active
+		GROUP BY u.user_id
+		ORDER BY u.user_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewer models.ReviewerOpenReviews
+		if err := rows.Scan(&reviewer.UserID, &reviewer.TeamName, &reviewer.OpenReviews); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		report.Reviewers = append(report.Reviewers, reviewer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return report, nil
+}
+
+// ReserveIdempotencyKey - занять ключ идемпотентности API-ключа apiKeyID на ttl. Возвращает nil, если ключ
+// занят этим вызовом, иначе - запись, сохранённую первым запросом с этим ключом. Истёкшие ключи удаляются.
+// Ключ без сохранённого ответа, занятый раньше чем lease назад, считается брошенным (процесс упал
+// посреди запроса) и занимается заново
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, apiKeyID, key, requestHash string, ttl, lease time.Duration) (*models.IdempotencyRecord, error) {
+	const op = "storage.postgres.ReserveIdempotencyKey"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`); err != nil {
+		return nil, fmt.Errorf("%s: failed to delete expired keys: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (api_key_id, idempotency_key, request_hash, reserved_at, expires_at)
+		VALUES ($1, $2, $3, now(), now() + make_interval(secs => $4))
+		ON CONFLICT (api_key_id, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			reserved_at = EXCLUDED.reserved_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.response_status IS NULL
+			AND idempotency_keys.reserved_at <= now() - make_interval(secs => $5)
+	`, apiKeyID, key, requestHash, ttl.Seconds(), lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to reserve key: %w", op, err)
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var record *models.IdempotencyRecord
+	if inserted == 0 {
+		record = &models.IdempotencyRecord{APIKeyID: apiKeyID, Key: key}
+		var status sql.NullInt64
+		err = tx.QueryRowContext(ctx, `
+			SELECT request_hash, response_status, response_body
+			FROM idempotency_keys
+			WHERE api_key_id = $1 AND idempotency_key = $2
+		`, apiKeyID, key).Scan(&record.RequestHash, &status, &record.ResponseBody)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to get key: %w", op, err)
+		}
+		record.Completed = status.Valid
+		record.ResponseStatus = int(status.Int64)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return record, nil
+}
+
+// CompleteIdempotencyKey - сохранить ответ на запрос с ключом идемпотентности
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, apiKeyID, key string, status int, body []byte) error {
+	const op = "storage.postgres.CompleteIdempotencyKey"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET response_status = $3, response_body = $4
+		WHERE api_key_id = $1 AND idempotency_key = $2
+	`, apiKeyID, key, status, body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey - освободить ключ, ответ на который не сохраняется
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, apiKeyID, key string) error {
+	const op = "storage.postgres.ReleaseIdempotencyKey"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE api_key_id = $1 AND idempotency_key = $2 AND response_status IS NULL
+	`, apiKeyID, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// CreateAPIKey - сохранить выданный API-ключ по его хэшу. Возвращает ключ с временем создания
+func (s *Storage) CreateAPIKey(ctx context.Context, key models.APIKey, keyHash string) (*models.APIKey, error) {
+	const op = "storage.postgres.CreateAPIKey"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (key_id, key_hash, name, role, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, key.KeyID, keyHash, key.Name, key.Role, key.CreatedBy).Scan(&key.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyExists)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &key, nil
+}
+
+// GetAPIKeyByHash - API-ключ по хэшу, в том числе отозванный
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	const op = "storage.postgres.GetAPIKeyByHash"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, `
+		SELECT key_id, name, role, created_by, created_at, revoked_at, COALESCE(revoked_by, '')
+		FROM api_keys
+		WHERE key_hash = $1
+	`, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// RevokeAPIKey - отозвать API-ключ. Повторный отзыв не меняет время и автора первого
+func (s *Storage) RevokeAPIKey(ctx context.Context, keyID, actor string) (*models.APIKey, error) {
+	const op = "storage.postgres.RevokeAPIKey"
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, now()), revoked_by = COALESCE(revoked_by, $2)
+		WHERE key_id = $1
+		RETURNING key_id, name, role, created_by, created_at, revoked_at, COALESCE(revoked_by, '')
+	`, keyID, actor))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// scanAPIKey - прочитать API-ключ из строки результата
+func scanAPIKey(row *sql.Row) (*models.APIKey, error) {
+	var key models.APIKey
+	var revokedAt sql.NullTime
+	if err := row.Scan(&key.KeyID, &key.Name, &key.Role, &key.CreatedBy, &key.CreatedAt, &revokedAt, &key.RevokedBy); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
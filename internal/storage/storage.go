@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"main.go/internal/models"
+)
+
+// Классы ошибок хранилища: по ним выбирается HTTP-статус
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+)
+
+// Ошибки хранилища, по которым обработчики выбирают код ответа.
+// Каждая оборачивает свой класс, так что errors.Is(err, ErrNotFound) верно и для ErrPRNotFound
+var (
+	ErrPRNotFound     = fmt.Errorf("pull request %w", ErrNotFound)
+	ErrUserNotFound   = fmt.Errorf("user %w", ErrNotFound)
+	ErrTeamNotFound   = fmt.Errorf("team %w", ErrNotFound)
+	ErrAuthorNotFound = fmt.Errorf("author %w", ErrNotFound)
+	ErrNotMember      = fmt.Errorf("team member %w", ErrNotFound)
+	ErrAPIKeyNotFound = fmt.Errorf("api key %w", ErrNotFound)
+
+	ErrPRExists         = fmt.Errorf("pull request already exists: %w", ErrConflict)
+	ErrTeamExists       = fmt.Errorf("team already exists with same members: %w", ErrConflict)
+	ErrPRMerged         = fmt.Errorf("pull request is merged: %w", ErrConflict)
+	ErrPRNotOpen        = fmt.Errorf("pull request is not open: %w", ErrConflict)
+	ErrNotAssigned      = fmt.Errorf("reviewer is not assigned to this pull request: %w", ErrConflict)
+	ErrAlreadyAssigned  = fmt.Errorf("reviewer is already assigned to this pull request: %w", ErrConflict)
+	ErrReviewerInactive = fmt.Errorf("reviewer is not active: %w", ErrConflict)
+	ErrNoCandidate      = fmt.Errorf("no active replacement candidate: %w", ErrConflict)
+	ErrAPIKeyExists     = fmt.Errorf("api key already exists: %w", ErrConflict)
+
+	// ErrInvalidSettings - настройки команды нарушают ограничения БД (например, лимиты ревьюеров)
+	ErrInvalidSettings = errors.New("invalid team settings")
+
+	// ErrMigrationsPending - в БД применены не все миграции схемы
+	ErrMigrationsPending = errors.New("database migrations are pending")
+)
+
+// PlanReader - данные для подбора ревьюеров. Хранилище передаёт его в ReassignmentPlanner,
+// чтобы план читал составы команд и нагрузку внутри той же транзакции, что и применяет его
+type PlanReader interface {
+	GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error)
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
+	GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+// ReassignmentPlanner - подобрать замену уходящим ревьюерам (leaving) на открытых PR prs.
+// Хранилище вызывает его, заблокировав эти PR, и применяет возвращённые замены
+type ReassignmentPlanner func(ctx context.Context, reader PlanReader, prs []models.PullRequest, leaving []string) ([]models.Reassignment, error)
+
+// Storage - все операции хранилища, которые используют обработчики, подбор ревьюеров
+// и middleware. Реализации: postgres.Storage и memory.Storage
+type Storage interface {
+	// Ping - проверить соединение с хранилищем
+	Ping(ctx context.Context) error
+	// CheckMigrations - проверить, что схема хранилища в актуальной версии (ErrMigrationsPending)
+	CheckMigrations(ctx context.Context) error
+	// Close - освободить ресурсы хранилища (соединения с БД) при остановке сервиса
+	Close() error
+
+	SaveTeamWithUpdate(ctx context.Context, team models.Team) (bool, error)
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamByUser(ctx context.Context, userID string) (*models.Team, error)
+	GetActiveMembersByTeam(ctx context.Context, teamName string) ([]string, error)
+	GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error)
+
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	CheckUserExists(ctx context.Context, userID string) error
+	SetUserActive(ctx context.Context, userID string, isActive bool, actor string) (*models.User, error)
+	DeactivateUser(ctx context.Context, userID string, planner ReassignmentPlanner, actor string) (*models.User, *models.ReassignmentReport, error)
+	DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string, planner ReassignmentPlanner, actor string) ([]models.User, *models.ReassignmentReport, error)
+
+	CreatePullRequest(ctx context.Context, pr models.PullRequest, actor string) error
+	GetPullRequestByID(ctx context.Context, pullRequestID string) (*models.PullRequest, error)
+	ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequest, *models.PageCursor, error)
+	MergePullRequest(ctx context.Context, pullRequestID string, override *models.MergeOverride, actor string) (*models.PullRequest, error)
+	ChangePullRequestStatus(ctx context.Context, pullRequestID string, transition models.Transition, reviewers []string, planner ReassignmentPlanner, actor string) (*models.PullRequest, *models.ReassignmentReport, error)
+	ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID, newReviewerID, actor string) error
+	IsReviewerAssigned(ctx context.Context, pullRequestID, userID string) (bool, error)
+	SubmitReview(ctx context.Context, pullRequestID, reviewerID, decision, comment, actor string) error
+	GetPullRequestReviews(ctx context.Context, pullRequestID string) ([]models.Review, error)
+	GetUserAssignedPullRequests(ctx context.Context, filter models.ReviewFilter) ([]models.PullRequestShort, *models.PageCursor, error)
+	GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]models.AssignmentEvent, error)
+
+	GetReviewerStats(ctx context.Context, filter models.StatsFilter) ([]models.ReviewerStats, error)
+	GetPullRequestStats(ctx context.Context, filter models.StatsFilter) (*models.PullRequestStatsReport, error)
+	GetOpenPullRequestsReport(ctx context.Context) (*models.OpenPullRequestsReport, error)
+
+	ReserveIdempotencyKey(ctx context.Context, apiKeyID, key, requestHash string, ttl, lease time.Duration) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, apiKeyID, key string, status int, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, apiKeyID, key string) error
+
+	CreateAPIKey(ctx context.Context, key models.APIKey, keyHash string) (*models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID, actor string) (*models.APIKey, error)
+}
+
+// NewReassignmentReport - отчёт о переназначении ревью уходящих ревьюеров (leaving) на PR prs:
+// применённые замены и ревью, оставшиеся без замены (пустые списки вместо null)
+func NewReassignmentReport(prs []models.PullRequest, leaving []string, applied []models.Reassignment) *models.ReassignmentReport {
+	report := &models.ReassignmentReport{
+		Reassigned:  append([]models.Reassignment{}, applied...),
+		NoCandidate: []models.Reassignment{},
+	}
+
+	for _, pr := range prs {
+		for _, reviewer := range pr.AssignedReviewers {
+			if !slices.Contains(leaving, reviewer) {
+				continue
+			}
+			reassigned := slices.ContainsFunc(applied, func(move models.Reassignment) bool {
+				return move.PullRequestID == pr.PullRequestID && move.OldReviewerID == reviewer
+			})
+			if !reassigned {
+				report.NoCandidate = append(report.NoCandidate, models.Reassignment{
+					PullRequestID: pr.PullRequestID,
+					OldReviewerID: reviewer,
+				})
+			}
+		}
+	}
+
+	return report
+}